@@ -0,0 +1,491 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: api.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DepositsClient is the client API for Deposits service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DepositsClient interface {
+	// GetDeposits decodes the deposits of the Solana transaction the same way they are broadcasted to core.
+	GetDeposits(ctx context.Context, in *GetDepositsRequest, opts ...grpc.CallOption) (*GetDepositsResponse, error)
+	// SubscribeDeposits streams the deposits as soon as they are processed by the saver.
+	// Stream starts after the cursor if it is set, otherwise only new deposits are streamed.
+	// Returns OUT_OF_RANGE if the cursor is unknown or the client is too slow and some deposits were evicted.
+	SubscribeDeposits(ctx context.Context, in *SubscribeDepositsRequest, opts ...grpc.CallOption) (Deposits_SubscribeDepositsClient, error)
+}
+
+type depositsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDepositsClient(cc grpc.ClientConnInterface) DepositsClient {
+	return &depositsClient{cc}
+}
+
+func (c *depositsClient) GetDeposits(ctx context.Context, in *GetDepositsRequest, opts ...grpc.CallOption) (*GetDepositsResponse, error) {
+	out := new(GetDepositsResponse)
+	err := c.cc.Invoke(ctx, "/solsaver.Deposits/GetDeposits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *depositsClient) SubscribeDeposits(ctx context.Context, in *SubscribeDepositsRequest, opts ...grpc.CallOption) (Deposits_SubscribeDepositsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Deposits_ServiceDesc.Streams[0], "/solsaver.Deposits/SubscribeDeposits", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &depositsSubscribeDepositsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Deposits_SubscribeDepositsClient interface {
+	Recv() (*DepositEvent, error)
+	grpc.ClientStream
+}
+
+type depositsSubscribeDepositsClient struct {
+	grpc.ClientStream
+}
+
+func (x *depositsSubscribeDepositsClient) Recv() (*DepositEvent, error) {
+	m := new(DepositEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DepositsServer is the server API for Deposits service.
+// All implementations must embed UnimplementedDepositsServer
+// for forward compatibility
+type DepositsServer interface {
+	// GetDeposits decodes the deposits of the Solana transaction the same way they are broadcasted to core.
+	GetDeposits(context.Context, *GetDepositsRequest) (*GetDepositsResponse, error)
+	// SubscribeDeposits streams the deposits as soon as they are processed by the saver.
+	// Stream starts after the cursor if it is set, otherwise only new deposits are streamed.
+	// Returns OUT_OF_RANGE if the cursor is unknown or the client is too slow and some deposits were evicted.
+	SubscribeDeposits(*SubscribeDepositsRequest, Deposits_SubscribeDepositsServer) error
+	mustEmbedUnimplementedDepositsServer()
+}
+
+// UnimplementedDepositsServer must be embedded to have forward compatible implementations.
+type UnimplementedDepositsServer struct {
+}
+
+func (UnimplementedDepositsServer) GetDeposits(context.Context, *GetDepositsRequest) (*GetDepositsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeposits not implemented")
+}
+func (UnimplementedDepositsServer) SubscribeDeposits(*SubscribeDepositsRequest, Deposits_SubscribeDepositsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeDeposits not implemented")
+}
+func (UnimplementedDepositsServer) mustEmbedUnimplementedDepositsServer() {}
+
+// UnsafeDepositsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DepositsServer will
+// result in compilation errors.
+type UnsafeDepositsServer interface {
+	mustEmbedUnimplementedDepositsServer()
+}
+
+func RegisterDepositsServer(s grpc.ServiceRegistrar, srv DepositsServer) {
+	s.RegisterService(&Deposits_ServiceDesc, srv)
+}
+
+func _Deposits_GetDeposits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDepositsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DepositsServer).GetDeposits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/solsaver.Deposits/GetDeposits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DepositsServer).GetDeposits(ctx, req.(*GetDepositsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Deposits_SubscribeDeposits_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeDepositsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DepositsServer).SubscribeDeposits(m, &depositsSubscribeDepositsServer{stream})
+}
+
+type Deposits_SubscribeDepositsServer interface {
+	Send(*DepositEvent) error
+	grpc.ServerStream
+}
+
+type depositsSubscribeDepositsServer struct {
+	grpc.ServerStream
+}
+
+func (x *depositsSubscribeDepositsServer) Send(m *DepositEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Deposits_ServiceDesc is the grpc.ServiceDesc for Deposits service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Deposits_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "solsaver.Deposits",
+	HandlerType: (*DepositsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDeposits",
+			Handler:    _Deposits_GetDeposits_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeDeposits",
+			Handler:       _Deposits_SubscribeDeposits_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	// StartCatchup starts the catchup of bridge transactions in background.
+	// Returns FAILED_PRECONDITION if another catchup job is running.
+	StartCatchup(ctx context.Context, in *StartCatchupRequest, opts ...grpc.CallOption) (*CatchupJob, error)
+	// GetCatchup returns the catchup job status and progress.
+	GetCatchup(ctx context.Context, in *GetCatchupRequest, opts ...grpc.CallOption) (*CatchupJob, error)
+	// CancelCatchup stops the running catchup job.
+	CancelCatchup(ctx context.Context, in *CancelCatchupRequest, opts ...grpc.CallOption) (*CatchupJob, error)
+	// BatchRevote verifies and votes for the listed operations or for all unvoted transfers from this chain
+	// created in the core blocks range. Result of every operation is streamed as soon as it is processed.
+	BatchRevote(ctx context.Context, in *BatchRevoteRequest, opts ...grpc.CallOption) (Admin_BatchRevoteClient, error)
+	// GetPause returns the broadcasting pause state.
+	GetPause(ctx context.Context, in *GetPauseRequest, opts ...grpc.CallOption) (*PauseState, error)
+	// SetPause pauses or resumes the saver deposits broadcasting and the voter voting, unset switches are not changed.
+	// Deposits observed while the saver is paused are kept pending and broadcasted after it is resumed,
+	// operations not voted while the voter is paused are voted after it is resumed.
+	SetPause(ctx context.Context, in *SetPauseRequest, opts ...grpc.CallOption) (*PauseState, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) StartCatchup(ctx context.Context, in *StartCatchupRequest, opts ...grpc.CallOption) (*CatchupJob, error) {
+	out := new(CatchupJob)
+	err := c.cc.Invoke(ctx, "/solsaver.Admin/StartCatchup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetCatchup(ctx context.Context, in *GetCatchupRequest, opts ...grpc.CallOption) (*CatchupJob, error) {
+	out := new(CatchupJob)
+	err := c.cc.Invoke(ctx, "/solsaver.Admin/GetCatchup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) CancelCatchup(ctx context.Context, in *CancelCatchupRequest, opts ...grpc.CallOption) (*CatchupJob, error) {
+	out := new(CatchupJob)
+	err := c.cc.Invoke(ctx, "/solsaver.Admin/CancelCatchup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) BatchRevote(ctx context.Context, in *BatchRevoteRequest, opts ...grpc.CallOption) (Admin_BatchRevoteClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Admin_ServiceDesc.Streams[0], "/solsaver.Admin/BatchRevote", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adminBatchRevoteClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Admin_BatchRevoteClient interface {
+	Recv() (*RevoteResult, error)
+	grpc.ClientStream
+}
+
+type adminBatchRevoteClient struct {
+	grpc.ClientStream
+}
+
+func (x *adminBatchRevoteClient) Recv() (*RevoteResult, error) {
+	m := new(RevoteResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adminClient) GetPause(ctx context.Context, in *GetPauseRequest, opts ...grpc.CallOption) (*PauseState, error) {
+	out := new(PauseState)
+	err := c.cc.Invoke(ctx, "/solsaver.Admin/GetPause", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetPause(ctx context.Context, in *SetPauseRequest, opts ...grpc.CallOption) (*PauseState, error) {
+	out := new(PauseState)
+	err := c.cc.Invoke(ctx, "/solsaver.Admin/SetPause", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	// StartCatchup starts the catchup of bridge transactions in background.
+	// Returns FAILED_PRECONDITION if another catchup job is running.
+	StartCatchup(context.Context, *StartCatchupRequest) (*CatchupJob, error)
+	// GetCatchup returns the catchup job status and progress.
+	GetCatchup(context.Context, *GetCatchupRequest) (*CatchupJob, error)
+	// CancelCatchup stops the running catchup job.
+	CancelCatchup(context.Context, *CancelCatchupRequest) (*CatchupJob, error)
+	// BatchRevote verifies and votes for the listed operations or for all unvoted transfers from this chain
+	// created in the core blocks range. Result of every operation is streamed as soon as it is processed.
+	BatchRevote(*BatchRevoteRequest, Admin_BatchRevoteServer) error
+	// GetPause returns the broadcasting pause state.
+	GetPause(context.Context, *GetPauseRequest) (*PauseState, error)
+	// SetPause pauses or resumes the saver deposits broadcasting and the voter voting, unset switches are not changed.
+	// Deposits observed while the saver is paused are kept pending and broadcasted after it is resumed,
+	// operations not voted while the voter is paused are voted after it is resumed.
+	SetPause(context.Context, *SetPauseRequest) (*PauseState, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) StartCatchup(context.Context, *StartCatchupRequest) (*CatchupJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartCatchup not implemented")
+}
+func (UnimplementedAdminServer) GetCatchup(context.Context, *GetCatchupRequest) (*CatchupJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCatchup not implemented")
+}
+func (UnimplementedAdminServer) CancelCatchup(context.Context, *CancelCatchupRequest) (*CatchupJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCatchup not implemented")
+}
+func (UnimplementedAdminServer) BatchRevote(*BatchRevoteRequest, Admin_BatchRevoteServer) error {
+	return status.Errorf(codes.Unimplemented, "method BatchRevote not implemented")
+}
+func (UnimplementedAdminServer) GetPause(context.Context, *GetPauseRequest) (*PauseState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPause not implemented")
+}
+func (UnimplementedAdminServer) SetPause(context.Context, *SetPauseRequest) (*PauseState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPause not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_StartCatchup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartCatchupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).StartCatchup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/solsaver.Admin/StartCatchup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).StartCatchup(ctx, req.(*StartCatchupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetCatchup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatchupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetCatchup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/solsaver.Admin/GetCatchup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetCatchup(ctx, req.(*GetCatchupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_CancelCatchup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelCatchupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CancelCatchup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/solsaver.Admin/CancelCatchup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CancelCatchup(ctx, req.(*CancelCatchupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_BatchRevote_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchRevoteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdminServer).BatchRevote(m, &adminBatchRevoteServer{stream})
+}
+
+type Admin_BatchRevoteServer interface {
+	Send(*RevoteResult) error
+	grpc.ServerStream
+}
+
+type adminBatchRevoteServer struct {
+	grpc.ServerStream
+}
+
+func (x *adminBatchRevoteServer) Send(m *RevoteResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Admin_GetPause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetPause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/solsaver.Admin/GetPause",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetPause(ctx, req.(*GetPauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetPause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetPause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/solsaver.Admin/SetPause",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetPause(ctx, req.(*SetPauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "solsaver.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartCatchup",
+			Handler:    _Admin_StartCatchup_Handler,
+		},
+		{
+			MethodName: "GetCatchup",
+			Handler:    _Admin_GetCatchup_Handler,
+		},
+		{
+			MethodName: "CancelCatchup",
+			Handler:    _Admin_CancelCatchup_Handler,
+		},
+		{
+			MethodName: "GetPause",
+			Handler:    _Admin_GetPause_Handler,
+		},
+		{
+			MethodName: "SetPause",
+			Handler:    _Admin_SetPause_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchRevote",
+			Handler:       _Admin_BatchRevote_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
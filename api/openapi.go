@@ -0,0 +1,58 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+//go:embed api.swagger.json
+var generated []byte
+
+// revote is the OpenAPI document of the Saver/Revote route, it is served by the hand-written gateway handler
+// because saver-grpc-lib does not provide the proto file to generate it from.
+//
+//go:embed revote.swagger.json
+var revote []byte
+
+// OpenAPI is the OpenAPI v2 document of the HTTP/JSON gateway generated from api.proto
+// with the Saver/Revote route added.
+var OpenAPI = mergeOpenAPI(generated, revote)
+
+type openAPI struct {
+	Swagger     string                     `json:"swagger"`
+	Info        json.RawMessage            `json:"info"`
+	Tags        []json.RawMessage          `json:"tags"`
+	Consumes    []string                   `json:"consumes"`
+	Produces    []string                   `json:"produces"`
+	Paths       map[string]json.RawMessage `json:"paths"`
+	Definitions map[string]json.RawMessage `json:"definitions"`
+}
+
+func mergeOpenAPI(base, extra []byte) []byte {
+	var doc, ext openAPI
+
+	if err := json.Unmarshal(base, &doc); err != nil {
+		panic(err)
+	}
+
+	if err := json.Unmarshal(extra, &ext); err != nil {
+		panic(err)
+	}
+
+	doc.Tags = append(doc.Tags, ext.Tags...)
+
+	for path, item := range ext.Paths {
+		doc.Paths[path] = item
+	}
+
+	for name, definition := range ext.Definitions {
+		doc.Definitions[name] = definition
+	}
+
+	result, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
@@ -2,18 +2,28 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"time"
 
 	"github.com/alecthomas/kingpin"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	"github.com/rarimo/saver-grpc-lib/voter"
 	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
 	"github.com/rarimo/sol-saver-svc/internal/config"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/sol-saver-svc/internal/service/gateway"
 	"github.com/rarimo/sol-saver-svc/internal/service/grpc"
+	"github.com/rarimo/sol-saver-svc/internal/service/pause"
+	"github.com/rarimo/sol-saver-svc/internal/service/saver"
 	"github.com/rarimo/sol-saver-svc/internal/service/saver/catchup"
 	"github.com/rarimo/sol-saver-svc/internal/service/saver/listener"
+	"github.com/rarimo/sol-saver-svc/internal/service/tokenmanager"
 	voterservice "github.com/rarimo/sol-saver-svc/internal/service/voter"
 	"gitlab.com/distributed_lab/kit/kv"
 	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
 func Run(args []string) bool {
@@ -25,9 +35,6 @@ func Run(args []string) bool {
 		}
 	}()
 
-	cfg := config.New(kv.MustFromEnv())
-	log = cfg.Log()
-
 	app := kingpin.New("sol-saver-svc", "")
 
 	runCmd := app.Command("run", "run command")
@@ -38,60 +45,151 @@ func Run(args []string) bool {
 
 	serviceCmd := runCmd.Command("service", "run service") // you can insert custom help
 
+	bundleCmd := app.Command("bundle", "bundle tools")
+	bundleDecodeCmd := bundleCmd.Command("decode", "decode hex encoded deposit bundle")
+	bundleData := bundleDecodeCmd.Arg("data", "hex encoded bundle data").Required().String()
+
+	reviewCmd := app.Command("review", "deposits flagged for refund")
+	reviewListCmd := reviewCmd.Command("list", "list deposits flagged for refund")
+
+	pauseCmd := app.Command("pause", "pause broadcasting of the running service")
+	pauseComponent := pauseCmd.Arg("component", "saver, voter or all").Required().Enum("saver", "voter", "all")
+	resumeCmd := app.Command("resume", "resume broadcasting of the running service")
+	resumeComponent := resumeCmd.Arg("component", "saver, voter or all").Required().Enum("saver", "voter", "all")
+	pauseStatusCmd := app.Command("pause-status", "show the pause state and pending deposits")
+
 	cmd, err := app.Parse(args[1:])
 	if err != nil {
 		log.WithError(err).Error("failed to parse arguments")
 		return false
 	}
 
+	// Tools are run without the service dependencies and read only the config they need
+	tools := map[string]func() error{
+		bundleDecodeCmd.FullCommand(): func() error { return decodeBundle(*bundleData) },
+		reviewListCmd.FullCommand():   func() error { return listReview(config.New(kv.MustFromEnv())) },
+		pauseCmd.FullCommand():        func() error { return setPause(config.New(kv.MustFromEnv()), *pauseComponent, true) },
+		resumeCmd.FullCommand():       func() error { return setPause(config.New(kv.MustFromEnv()), *resumeComponent, false) },
+		pauseStatusCmd.FullCommand():  func() error { return pauseStatus(config.New(kv.MustFromEnv())) },
+	}
+
+	if tool, ok := tools[cmd]; ok {
+		if err := tool(); err != nil {
+			log.WithError(err).Error("failed to exec cmd")
+			return false
+		}
+		return true
+	}
+
+	cfg := config.New(kv.MustFromEnv())
+	log = cfg.Log()
+
 	if profiler := cfg.Profiler(); profiler.Enabled {
 		profiler.RunProfiling()
 	}
 
 	switch cmd {
 	case voterCmd.FullCommand():
-		verifier := verifiers.NewTransferVerifier(
-			voterservice.NewTransferOperator(cfg),
-			cfg.Log(),
-		)
+		// Running token manager cache invalidation
+		go tokenmanager.NewInvalidator(cfg.TokenManager(), cfg.TendermintEvents(), cfg.Log(), cfg.Subscriber()).Run(context.Background())
+
+		operator := voterservice.NewTransferOperator(cfg)
+		verifier := verifiers.NewTransferVerifier(operator, cfg.Log())
 
-		v := voter.NewVoter(cfg.ListenConf().Chain, cfg.Log(), cfg.Broadcaster(), map[rarimotypes.OpType]voter.Verifier{
+		// Votes are not broadcasted while the voter is paused, unvoted operations are caught up on resume
+		v := voter.NewVoter(cfg.ListenConf().Chain, cfg.Log(), pause.NewBroadcaster(cfg.Broadcaster(), cfg.Pause(), pause.Voter), map[rarimotypes.OpType]voter.Verifier{
 			rarimotypes.OpType_TRANSFER: verifier,
 		})
+		cfg.Pause().OnResume(pause.Voter, func() {
+			voterservice.NewCatchupper(cfg.Cosmos(), v, cfg.Log(), cfg.VoterConf().Workers).Run(context.Background())
+		})
+		// Reloading the pause state changed by CLI
+		go cfg.Pause().Run(context.Background())
 
 		// Running catchup for unvoted operations
-		voter.NewCatchupper(cfg.Cosmos(), v, cfg.Log()).Run(context.TODO())
+		voterservice.NewCatchupper(cfg.Cosmos(), v, cfg.Log(), cfg.VoterConf().Workers).Run(context.TODO())
 		// Running subscriber for new operations
-		go voter.NewTransferSubscriber(v, cfg.Tendermint(), cfg.Cosmos(), cfg.Log(), cfg.Subscriber()).Run(context.Background())
+		go voterservice.NewTransferSubscriber(v, cfg.TendermintEvents(), cfg.Cosmos(), cfg.Log(), cfg.Subscriber(), cfg.VoterConf().Workers).Run(context.Background())
 
-		// Running GRPC server
-		err = grpc.NewSaverService(cfg.Log(), cfg.Listener(), v, cfg.Cosmos()).Run()
+		// Running dependencies health checks
+		go cfg.Health().Run(context.Background())
+
+		// Running HTTP/JSON gateway to the GRPC server
+		if cfg.GatewayConf().Enabled {
+			go gateway.NewService(cfg).Run(context.Background())
+		}
+
+		// Running GRPC server, deposits feed and pending deposits are served by the saver only
+		deposits := grpc.NewDepositsService(cfg.Log(), cfg.SolanaRPC(), saver.NewDecoder(cfg), nil, cfg.Cosmos(), cfg.ListenConf().Chain)
+		revoter := voterservice.NewRevoter(cfg, operator)
+		// Catchup jobs broadcast saver messages, so they are not available without the saver
+		admin := grpc.NewAdminService(cfg.Log(), nil, revoter, cfg.Pause(), cfg.PendingDeposits())
+		err = grpc.NewSaverService(cfg.Log(), cfg.Listener(), cfg.ListenerTLS(), cfg.Auth(), revoter, cfg.Health(), deposits, admin).Run()
 	case saverCmd.FullCommand():
+		// Running token manager cache invalidation
+		go tokenmanager.NewInvalidator(cfg.TokenManager(), cfg.TendermintEvents(), cfg.Log(), cfg.Subscriber()).Run(context.Background())
+		// Reloading the pause state changed by CLI
+		go cfg.Pause().Run(context.Background())
+
+		processor := saver.NewTxProcessor(cfg, nil, cfg.PendingDeposits())
+		// Broadcasting deposits kept pending while the saver was paused
+		go processor.FlushPending(context.Background())
+
 		// Running subscriber for new transaction on bridge
-		listener.NewService(cfg).Listen(context.TODO())
+		listener.NewService(cfg, processor).Listen(context.TODO())
 	case saverCatchupCmd.FullCommand():
+		// One-shot catchup can not flush pending deposits, so it is refused while the saver is paused
+		if cfg.Pause().Paused(pause.Saver) {
+			err = errors.New("saver is paused, resume it before running catchup")
+			break
+		}
+
 		// Running catchup for transaction on bridge
-		err = catchup.NewService(cfg).Catchup(context.TODO())
+		err = catchup.NewService(cfg, saver.NewTxProcessor(cfg, nil, nil)).Catchup(context.TODO())
 	case serviceCmd.FullCommand():
-		verifier := verifiers.NewTransferVerifier(
-			voterservice.NewTransferOperator(cfg),
-			cfg.Log(),
-		)
+		// Running token manager cache invalidation
+		go tokenmanager.NewInvalidator(cfg.TokenManager(), cfg.TendermintEvents(), cfg.Log(), cfg.Subscriber()).Run(context.Background())
+
+		operator := voterservice.NewTransferOperator(cfg)
+		verifier := verifiers.NewTransferVerifier(operator, cfg.Log())
 
-		v := voter.NewVoter(cfg.ListenConf().Chain, cfg.Log(), cfg.Broadcaster(), map[rarimotypes.OpType]voter.Verifier{
+		// Votes are not broadcasted while the voter is paused, unvoted operations are caught up on resume
+		v := voter.NewVoter(cfg.ListenConf().Chain, cfg.Log(), pause.NewBroadcaster(cfg.Broadcaster(), cfg.Pause(), pause.Voter), map[rarimotypes.OpType]voter.Verifier{
 			rarimotypes.OpType_TRANSFER: verifier,
 		})
+		cfg.Pause().OnResume(pause.Voter, func() {
+			voterservice.NewCatchupper(cfg.Cosmos(), v, cfg.Log(), cfg.VoterConf().Workers).Run(context.Background())
+		})
+		// Reloading the pause state changed by CLI
+		go cfg.Pause().Run(context.Background())
 
 		// Running catchup for unvoted operations
-		voter.NewCatchupper(cfg.Cosmos(), v, cfg.Log()).Run(context.TODO())
+		voterservice.NewCatchupper(cfg.Cosmos(), v, cfg.Log(), cfg.VoterConf().Workers).Run(context.TODO())
 
 		// Running subscriber for new operations
-		go voter.NewTransferSubscriber(v, cfg.Tendermint(), cfg.Cosmos(), cfg.Log(), cfg.Subscriber()).Run(context.Background())
+		go voterservice.NewTransferSubscriber(v, cfg.TendermintEvents(), cfg.Cosmos(), cfg.Log(), cfg.Subscriber(), cfg.VoterConf().Workers).Run(context.Background())
+		// Deposits processed by the listener are streamed to the gRPC feed subscribers
+		feed := saver.NewFeed(cfg.FeedConf().Buffer)
+		processor := saver.NewTxProcessor(cfg, feed, cfg.PendingDeposits())
+		// Broadcasting deposits kept pending while the saver was paused
+		go processor.FlushPending(context.Background())
+
 		// Running subscriber for new transaction on bridge
-		go listener.NewService(cfg).Listen(context.Background())
+		go listener.NewService(cfg, processor).Listen(context.Background())
+
+		// Running dependencies health checks
+		go cfg.Health().Run(context.Background())
+
+		// Running HTTP/JSON gateway to the GRPC server
+		if cfg.GatewayConf().Enabled {
+			go gateway.NewService(cfg).Run(context.Background())
+		}
 
 		// Running GRPC server
-		err = grpc.NewSaverService(cfg.Log(), cfg.Listener(), v, cfg.Cosmos()).Run()
+		deposits := grpc.NewDepositsService(cfg.Log(), cfg.SolanaRPC(), processor.Decoder, feed, cfg.Cosmos(), cfg.ListenConf().Chain)
+		revoter := voterservice.NewRevoter(cfg, operator)
+		admin := grpc.NewAdminService(cfg.Log(), catchup.NewJobs(cfg.Log(), catchup.NewService(cfg, processor)), revoter, cfg.Pause(), cfg.PendingDeposits())
+		err = grpc.NewSaverService(cfg.Log(), cfg.Listener(), cfg.ListenerTLS(), cfg.Auth(), revoter, cfg.Health(), deposits, admin).Run()
 	default:
 		log.Errorf("unknown command %s", cmd)
 		return false
@@ -102,3 +200,84 @@ func Run(args []string) bool {
 	}
 	return true
 }
+
+func decodeBundle(data string) error {
+	raw, err := hexutil.Decode(data)
+	if err != nil {
+		return errors.Wrap(err, "error decoding hex")
+	}
+
+	bundle, err := service.DecodeBundle(raw)
+	if err != nil {
+		return errors.Wrap(err, "error decoding bundle")
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundle)
+}
+
+func listReview(cfg config.Config) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for _, entry := range cfg.ReviewQueue().List() {
+		if err := encoder.Encode(entry); err != nil {
+			return errors.Wrap(err, "error encoding entry")
+		}
+	}
+
+	return nil
+}
+
+// setPause changes the persisted pause state, running service reloads it within the pause.period.
+func setPause(cfg config.Config, component string, paused bool) error {
+	components := pause.Components
+	if component != "all" {
+		c, err := pause.ParseComponent(component)
+		if err != nil {
+			return err
+		}
+
+		components = []pause.Component{c}
+	}
+
+	if _, err := pause.Update(cfg.PauseConf().Path, paused, components...); err != nil {
+		return errors.Wrap(err, "error setting pause state")
+	}
+
+	return pauseStatus(cfg)
+}
+
+func pauseStatus(cfg config.Config) error {
+	state, err := pause.Load(cfg.PauseConf().Path)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	type pending struct {
+		Tx       string    `json:"tx"`
+		EventId  string    `json:"event_id"`
+		QueuedAt time.Time `json:"queued_at"`
+	}
+
+	status := struct {
+		pause.State
+		PendingDeposits []pending `json:"pending_deposits"`
+	}{
+		State:           state,
+		PendingDeposits: []pending{},
+	}
+
+	deposits, err := cfg.PendingDeposits().List()
+	if err != nil {
+		return errors.Wrap(err, "error loading pending deposits")
+	}
+
+	for _, d := range deposits {
+		status.PendingDeposits = append(status.PendingDeposits, pending{Tx: d.Tx, EventId: d.EventId, QueuedAt: d.QueuedAt})
+	}
+
+	return encoder.Encode(status)
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/rarimo/sol-saver-svc/internal/service/auth"
+	"github.com/spf13/cast"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// AuthIdentity is the caller allowed by the bearer token or by the client certificate name.
+type AuthIdentity struct {
+	Name  string `fig:"name,required"`
+	Role  string `fig:"role,required"`
+	Token string `fig:"token"`
+}
+
+// Auth returns the gRPC authorizer or <nil> if authorization is disabled.
+func (c *config) Auth() *auth.Authorizer {
+	return c.auth.Do(func() interface{} {
+		var config struct {
+			Enabled bool `fig:"enabled"`
+			// Callers authenticated by the bearer token
+			Tokens []AuthIdentity `fig:"tokens"`
+			// Callers authenticated by the mTLS client certificate common name or DNS name
+			Clients []AuthIdentity `fig:"clients"`
+		}
+
+		if err := figure.Out(&config).
+			With(figure.BaseHooks, authHooks).
+			From(kv.MustGetStringMap(c.getter, "auth")).
+			Please(); err != nil {
+			panic(err)
+		}
+
+		if !config.Enabled {
+			return (*auth.Authorizer)(nil)
+		}
+
+		tokens := make(map[string]auth.Identity, len(config.Tokens))
+		for _, t := range config.Tokens {
+			if t.Token == "" {
+				panic(errors.From(errors.New("auth token is required"), logan.F{"name": t.Name}))
+			}
+
+			tokens[t.Token] = mustIdentity(t)
+		}
+
+		clients := make(map[string]auth.Identity, len(config.Clients))
+		for _, cl := range config.Clients {
+			clients[cl.Name] = mustIdentity(cl)
+		}
+
+		if len(tokens) != 0 && c.ListenerTLS() == nil {
+			c.Log().Warn("Auth tokens are sent over plaintext gRPC listener, enable listener TLS")
+		}
+
+		return auth.NewAuthorizer(c.Log(), tokens, clients)
+	}).(*auth.Authorizer)
+}
+
+func mustIdentity(i AuthIdentity) auth.Identity {
+	role, err := auth.ParseRole(i.Role)
+	if err != nil {
+		panic(errors.Wrap(err, "invalid auth identity", logan.F{"name": i.Name}))
+	}
+
+	return auth.Identity{Name: i.Name, Role: role}
+}
+
+var authHooks = figure.Hooks{
+	"[]config.AuthIdentity": func(raw interface{}) (reflect.Value, error) {
+		items, err := cast.ToSliceE(raw)
+		if err != nil {
+			return reflect.Value{}, errors.Wrap(err, "expected list")
+		}
+
+		result := make([]AuthIdentity, len(items))
+		for i, item := range items {
+			values, err := cast.ToStringMapE(item)
+			if err != nil {
+				return reflect.Value{}, errors.Wrap(err, "expected map")
+			}
+
+			if err := figure.Out(&result[i]).From(values).Please(); err != nil {
+				return reflect.Value{}, errors.Wrap(err, "failed to figure out auth identity", logan.F{"index": i})
+			}
+		}
+
+		return reflect.ValueOf(result), nil
+	},
+}
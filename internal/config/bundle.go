@@ -0,0 +1,28 @@
+package config
+
+import (
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+type BundleConf struct {
+	// Max size of the encoded bundle in bytes
+	MaxSize int `fig:"max_size"`
+	// Max amount of calls in the bundle
+	MaxCalls int `fig:"max_calls"`
+}
+
+func (c *config) BundleConf() BundleConf {
+	return c.bconf.Do(func() interface{} {
+		config := BundleConf{
+			MaxSize:  16 << 10,
+			MaxCalls: 16,
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "bundle")).Please(); err != nil {
+			panic(err)
+		}
+
+		return config
+	}).(BundleConf)
+}
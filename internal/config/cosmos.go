@@ -1,26 +1,44 @@
 package config
 
 import (
+	"crypto/tls"
+	nethttp "net/http"
 	"time"
 
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	"github.com/tendermint/tendermint/rpc/client/http"
 	"gitlab.com/distributed_lab/figure"
 	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 )
 
 func (c *config) Cosmos() *grpc.ClientConn {
 	return c.cosmos.Do(func() interface{} {
 		var config struct {
-			Addr string `fig:"addr"`
+			Addr string        `fig:"addr"`
+			TLS  ClientTLSConf `fig:"tls"`
 		}
 
 		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "cosmos")).Please(); err != nil {
 			panic(err)
 		}
 
-		con, err := grpc.Dial(config.Addr, grpc.WithInsecure(), grpc.WithKeepaliveParams(keepalive.ClientParameters{
+		tlsConfig, err := config.TLS.ClientConfig()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to configure cosmos TLS"))
+		}
+
+		creds := insecure.NewCredentials()
+		if tlsConfig != nil {
+			creds = credentials.NewTLS(tlsConfig)
+		}
+
+		con, err := grpc.Dial(config.Addr, grpc.WithTransportCredentials(creds), grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:    10 * time.Second, // wait time before ping if no activity
 			Timeout: 20 * time.Second, // ping timeout
 		}))
@@ -32,25 +50,82 @@ func (c *config) Cosmos() *grpc.ClientConn {
 	}).(*grpc.ClientConn)
 }
 
-func (c *config) Tendermint() *http.HTTP {
-	return c.tendermint.Do(func() interface{} {
+type coreConf struct {
+	Addr string
+	TLS  *tls.Config
+}
+
+func (c *config) coreConf() coreConf {
+	return c.core.Do(func() interface{} {
 		var config struct {
-			Addr string `fig:"addr"`
+			Addr string        `fig:"addr"`
+			TLS  ClientTLSConf `fig:"tls"`
 		}
 
 		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "core")).Please(); err != nil {
 			panic(err)
 		}
 
-		client, err := http.New(config.Addr, "/websocket")
+		tlsConfig, err := config.TLS.ClientConfig()
 		if err != nil {
-			panic(err)
+			panic(errors.Wrap(err, "failed to configure core TLS"))
 		}
 
-		if err := client.Start(); err != nil {
+		return coreConf{Addr: config.Addr, TLS: tlsConfig}
+	}).(coreConf)
+}
+
+func (c *config) Tendermint() *http.HTTP {
+	return c.tendermint.Do(func() interface{} {
+		config := c.coreConf()
+
+		if config.TLS == nil {
+			client, err := http.New(config.Addr, "/websocket")
+			if err != nil {
+				panic(err)
+			}
+
+			if err := client.Start(); err != nil {
+				panic(err)
+			}
+
+			return client
+		}
+
+		// Websocket of this client is not started: it can not use the custom TLS config,
+		// subscriptions are made with TendermintEvents.
+		client, err := http.NewWithClient(config.Addr, "/websocket", &nethttp.Client{
+			Transport: &nethttp.Transport{
+				Proxy:           nethttp.ProxyFromEnvironment,
+				TLSClientConfig: config.TLS,
+			},
+		})
+		if err != nil {
 			panic(err)
 		}
 
 		return client
 	}).(*http.HTTP)
 }
+
+// TendermintEvents returns the client for the tendermint events subscriptions.
+func (c *config) TendermintEvents() rpcclient.EventsClient {
+	return c.tmEvents.Do(func() interface{} {
+		config := c.coreConf()
+
+		if config.TLS == nil {
+			return c.Tendermint()
+		}
+
+		events, err := service.NewWSEvents(c.Log(), config.Addr, "/websocket", config.TLS)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to create tendermint events client"))
+		}
+
+		if err := events.Start(); err != nil {
+			panic(err)
+		}
+
+		return events
+	}).(rpcclient.EventsClient)
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type FeedConf struct {
+	// Max amount of the last deposits kept to resume the feed subscriptions
+	Buffer int `fig:"buffer"`
+}
+
+func (c *config) FeedConf() FeedConf {
+	return c.fconf.Do(func() interface{} {
+		config := FeedConf{
+			Buffer: 10000,
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "feed")).Please(); err != nil {
+			panic(err)
+		}
+
+		if config.Buffer <= 0 {
+			panic(errors.New("feed.buffer should be positive"))
+		}
+
+		return config
+	}).(FeedConf)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"crypto/tls"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type GatewayConf struct {
+	Enabled bool
+	// Address of the HTTP/JSON gateway listener
+	Addr string
+	// Server TLS config of the gateway listener, HTTP is served if <nil>
+	TLS *tls.Config
+	// Client TLS config of the connection to the service gRPC listener, plaintext if <nil>
+	GRPCTLS *tls.Config
+}
+
+func (c *config) GatewayConf() GatewayConf {
+	return c.gateway.Do(func() interface{} {
+		var config = struct {
+			Enabled bool          `fig:"enabled"`
+			Addr    string        `fig:"addr"`
+			Cert    string        `fig:"cert"`
+			Key     string        `fig:"key"`
+			GRPCTLS ClientTLSConf `fig:"grpc_tls"`
+		}{
+			Addr: ":8080",
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "gateway")).Please(); err != nil {
+			panic(err)
+		}
+
+		result := GatewayConf{
+			Enabled: config.Enabled,
+			Addr:    config.Addr,
+		}
+
+		if config.Cert != "" || config.Key != "" {
+			cert, err := tls.LoadX509KeyPair(config.Cert, config.Key)
+			if err != nil {
+				panic(errors.Wrap(err, "error loading gateway certificate"))
+			}
+
+			result.TLS = &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{cert},
+			}
+		}
+
+		grpcTLS, err := config.GRPCTLS.ClientConfig()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to configure gateway gRPC TLS"))
+		}
+
+		result.GRPCTLS = grpcTLS
+		return result
+	}).(GatewayConf)
+}
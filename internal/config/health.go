@@ -0,0 +1,29 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rarimo/sol-saver-svc/internal/service/health"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+func (c *config) Health() *health.Monitor {
+	return c.health.Do(func() interface{} {
+		var config = struct {
+			// Period of the dependencies health checks
+			Period time.Duration `fig:"period"`
+			// Max duration of the single health check
+			Timeout time.Duration `fig:"timeout"`
+		}{
+			Period:  10 * time.Second,
+			Timeout: 5 * time.Second,
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "health")).Please(); err != nil {
+			panic(err)
+		}
+
+		return health.NewMonitor(c.Log(), c.SolanaRPC(), c.Cosmos(), config.Period, config.Timeout)
+	}).(*health.Monitor)
+}
@@ -14,6 +14,10 @@ type ListenConf struct {
 	ProgramId solana.PublicKey `fig:"program_id"`
 	FromTx    solana.Signature `fig:"from_tx"`
 	Chain     string           `fig:"chain"`
+	// Optional messages that bridge program logs on deposits. Not checked if empty.
+	NativeDepositLog string `fig:"native_deposit_log"`
+	FTDepositLog     string `fig:"ft_deposit_log"`
+	NFTDepositLog    string `fig:"nft_deposit_log"`
 }
 
 func (c *config) ListenConf() ListenConf {
@@ -1,10 +1,19 @@
 package config
 
 import (
+	"crypto/tls"
+
 	"github.com/olegfomenko/solana-go/rpc"
 	"github.com/rarimo/saver-grpc-lib/broadcaster"
 	"github.com/rarimo/saver-grpc-lib/metrics"
 	"github.com/rarimo/saver-grpc-lib/voter"
+	"github.com/rarimo/sol-saver-svc/internal/service/auth"
+	"github.com/rarimo/sol-saver-svc/internal/service/health"
+	"github.com/rarimo/sol-saver-svc/internal/service/metadata"
+	"github.com/rarimo/sol-saver-svc/internal/service/pause"
+	"github.com/rarimo/sol-saver-svc/internal/service/review"
+	"github.com/rarimo/sol-saver-svc/internal/service/tokenmanager"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	"github.com/tendermint/tendermint/rpc/client/http"
 	"gitlab.com/distributed_lab/kit/comfig"
 	"gitlab.com/distributed_lab/kit/kv"
@@ -18,11 +27,27 @@ type Config interface {
 	voter.Subscriberer
 	metrics.Profilerer
 
+	ListenerTLS() *tls.Config
+	Auth() *auth.Authorizer
+	GatewayConf() GatewayConf
+	PauseConf() PauseConf
+	Pause() *pause.Switch
+	PendingDeposits() *pause.Pending
 	Cosmos() *grpc.ClientConn
 	Tendermint() *http.HTTP
+	TendermintEvents() rpcclient.EventsClient
+	TokenManager() *tokenmanager.Cache
+	MetadataFetcher() *metadata.Fetcher
 	ListenConf() ListenConf
+	VoterConf() VoterConf
+	NFTConf() NFTConf
+	BundleConf() BundleConf
+	ReviewQueue() *review.Queue
+	Health() *health.Monitor
+	FeedConf() FeedConf
 	SolanaRPC() *rpc.Client
 	SolanaWSEndpoint() string
+	SolanaQuorum() QuorumConf
 }
 
 type config struct {
@@ -32,11 +57,28 @@ type config struct {
 	voter.Subscriberer
 	metrics.Profilerer
 
-	cosmos     comfig.Once
-	tendermint comfig.Once
-	lconf      comfig.Once
-	solRPC     comfig.Once
-	solWS      comfig.Once
+	listenerTLS  comfig.Once
+	auth         comfig.Once
+	gateway      comfig.Once
+	pconf        comfig.Once
+	pause        comfig.Once
+	pending      comfig.Once
+	cosmos       comfig.Once
+	core         comfig.Once
+	tendermint   comfig.Once
+	tmEvents     comfig.Once
+	tokenManager comfig.Once
+	metadata     comfig.Once
+	lconf        comfig.Once
+	vconf        comfig.Once
+	nconf        comfig.Once
+	bconf        comfig.Once
+	review       comfig.Once
+	health       comfig.Once
+	fconf        comfig.Once
+	solRPC       comfig.Once
+	solWS        comfig.Once
+	solQuorum    comfig.Once
 
 	getter kv.Getter
 }
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rarimo/sol-saver-svc/internal/service/metadata"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+func (c *config) MetadataFetcher() *metadata.Fetcher {
+	return c.metadata.Do(func() interface{} {
+		config := metadata.Config{
+			IPFSGateway:          "https://ipfs.io/ipfs/",
+			ArweaveGateway:       "https://arweave.net/",
+			Timeout:              30 * time.Second,
+			MaxMetadataSize:      1 << 20,
+			MaxImageSize:         20 << 20,
+			MetadataContentTypes: []string{"application/json", "text/plain", "application/octet-stream"},
+			ImageContentTypes:    []string{"image/", "application/octet-stream"},
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "metadata")).Please(); err != nil {
+			panic(err)
+		}
+
+		return metadata.NewFetcher(config)
+	}).(*metadata.Fetcher)
+}
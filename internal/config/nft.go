@@ -0,0 +1,69 @@
+package config
+
+import (
+	"github.com/olegfomenko/solana-go"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// CollectionPolicy defines how NFTs without verified collection are handled
+type CollectionPolicy string
+
+const (
+	// CollectionPolicyReject refuses NFTs without verified collection
+	CollectionPolicyReject CollectionPolicy = "reject"
+	// CollectionPolicyStandalone treats NFTs without verified collection as standalone items (collection address = mint)
+	CollectionPolicyStandalone CollectionPolicy = "standalone"
+	// CollectionPolicyAllowlist accepts unverified collections from the allowlist
+	// if the token and the collection have the same update authority
+	CollectionPolicyAllowlist CollectionPolicy = "allowlist"
+)
+
+type NFTConf struct {
+	// Token Auth Rules rule sets that allow the bridge to hold and withdraw programmable NFTs.
+	// Programmable NFTs with other rule sets are refused.
+	RuleSets         map[solana.PublicKey]struct{}
+	CollectionPolicy CollectionPolicy
+	Collections      map[solana.PublicKey]struct{}
+}
+
+func (c *config) NFTConf() NFTConf {
+	return c.nconf.Do(func() interface{} {
+		config := struct {
+			RuleSets         []string `fig:"rule_sets"`
+			CollectionPolicy string   `fig:"collection_policy"`
+			Collections      []string `fig:"collections"`
+		}{
+			CollectionPolicy: string(CollectionPolicyStandalone),
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "nft")).Please(); err != nil {
+			panic(err)
+		}
+
+		result := NFTConf{
+			RuleSets:         toKeySet(config.RuleSets),
+			CollectionPolicy: CollectionPolicy(config.CollectionPolicy),
+			Collections:      toKeySet(config.Collections),
+		}
+
+		switch result.CollectionPolicy {
+		case CollectionPolicyReject, CollectionPolicyStandalone, CollectionPolicyAllowlist:
+		default:
+			panic(errors.From(errors.New("unknown collection policy"), logan.F{"policy": config.CollectionPolicy}))
+		}
+
+		return result
+	}).(NFTConf)
+}
+
+func toKeySet(keys []string) map[solana.PublicKey]struct{} {
+	result := make(map[solana.PublicKey]struct{}, len(keys))
+	for _, key := range keys {
+		result[solana.MustPublicKeyFromBase58(key)] = struct{}{}
+	}
+
+	return result
+}
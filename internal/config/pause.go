@@ -0,0 +1,56 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rarimo/sol-saver-svc/internal/service/pause"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+type PauseConf struct {
+	// File persisting the pause state
+	Path string `fig:"path"`
+	// Period of reloading the state changed by CLI
+	Period time.Duration `fig:"period"`
+	// File persisting the deposits observed while the saver is paused
+	PendingPath string `fig:"pending_path"`
+}
+
+func (c *config) PauseConf() PauseConf {
+	return c.pconf.Do(func() interface{} {
+		config := PauseConf{
+			Path:        "pause.json",
+			Period:      time.Second,
+			PendingPath: "pending_deposits.json",
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "pause")).Please(); err != nil {
+			panic(err)
+		}
+
+		return config
+	}).(PauseConf)
+}
+
+func (c *config) Pause() *pause.Switch {
+	return c.pause.Do(func() interface{} {
+		sw, err := pause.NewSwitch(c.Log(), c.PauseConf().Path, c.PauseConf().Period)
+		if err != nil {
+			panic(err)
+		}
+
+		return sw
+	}).(*pause.Switch)
+}
+
+func (c *config) PendingDeposits() *pause.Pending {
+	return c.pending.Do(func() interface{} {
+		pending, err := pause.NewPending(c.PauseConf().PendingPath)
+		if err != nil {
+			panic(err)
+		}
+
+		return pending
+	}).(*pause.Pending)
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"github.com/rarimo/sol-saver-svc/internal/service/review"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+func (c *config) ReviewQueue() *review.Queue {
+	return c.review.Do(func() interface{} {
+		var config = struct {
+			// Path to the file persisting deposits flagged for refund, queue is kept in memory if empty
+			Path string `fig:"path"`
+		}{
+			Path: "review_queue.jsonl",
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "review")).Please(); err != nil {
+			panic(err)
+		}
+
+		queue, err := review.NewQueue(config.Path)
+		if err != nil {
+			panic(err)
+		}
+
+		return queue
+	}).(*review.Queue)
+}
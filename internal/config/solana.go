@@ -4,8 +4,16 @@ import (
 	"github.com/olegfomenko/solana-go/rpc"
 	"gitlab.com/distributed_lab/figure"
 	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
+// QuorumConf describes the set of independent Solana RPC providers used by the voter.
+// Empty Providers means that quorum verification is disabled.
+type QuorumConf struct {
+	Threshold int
+	Providers []*rpc.Client
+}
+
 func (c *config) SolanaRPC() *rpc.Client {
 	return c.solRPC.Do(func() interface{} {
 		var config struct {
@@ -31,3 +39,35 @@ func (c *config) SolanaWSEndpoint() string {
 		return config.Url
 	}).(string)
 }
+
+func (c *config) SolanaQuorum() QuorumConf {
+	return c.solQuorum.Do(func() interface{} {
+		var config struct {
+			Enabled   bool     `fig:"enabled"`
+			Threshold int      `fig:"threshold"`
+			Urls      []string `fig:"urls"`
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "quorum")).Please(); err != nil {
+			panic(err)
+		}
+
+		if !config.Enabled {
+			return QuorumConf{}
+		}
+
+		if config.Threshold <= 0 || config.Threshold > len(config.Urls) {
+			panic(errors.New("quorum threshold should be in range [1, len(urls)]"))
+		}
+
+		providers := make([]*rpc.Client, 0, len(config.Urls))
+		for _, url := range config.Urls {
+			providers = append(providers, rpc.New(url))
+		}
+
+		return QuorumConf{
+			Threshold: config.Threshold,
+			Providers: providers,
+		}
+	}).(QuorumConf)
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// ClientTLSConf describes the TLS connection to the core node.
+type ClientTLSConf struct {
+	Enabled bool `fig:"enabled"`
+	// PEM encoded CA bundle verifying the server certificate, system pool is used if empty
+	CA string `fig:"ca"`
+	// Optional PEM encoded client certificate and key for mTLS
+	Cert string `fig:"cert"`
+	Key  string `fig:"key"`
+	// Overrides the server name verified in the certificate, host of the address is used if empty
+	ServerName string `fig:"server_name"`
+}
+
+// ListenerTLSConf describes the TLS of the service gRPC listener.
+type ListenerTLSConf struct {
+	Enabled bool   `fig:"enabled"`
+	Cert    string `fig:"cert"`
+	Key     string `fig:"key"`
+	// PEM encoded CA bundle verifying the client certificates. Enables mTLS if not empty.
+	ClientCA string `fig:"client_ca"`
+	// Common names or DNS names of the client certificates allowed to connect.
+	// Any client with the valid certificate is allowed if empty.
+	AllowedClients []string `fig:"allowed_clients"`
+}
+
+// ClientConfig returns the client TLS config or <nil> if TLS is disabled.
+func (t ClientTLSConf) ClientConfig() (*tls.Config, error) {
+	if !t.Enabled {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: t.ServerName,
+	}
+
+	if t.CA != "" {
+		pool, err := loadCertPool(t.CA)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = pool
+	}
+
+	if t.Cert != "" || t.Key != "" {
+		cert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading client certificate", logan.F{"cert": t.Cert})
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// ServerConfig returns the server TLS config or <nil> if TLS is disabled.
+func (t ListenerTLSConf) ServerConfig() (*tls.Config, error) {
+	if !t.Enabled {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading server certificate", logan.F{"cert": t.Cert})
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if t.ClientCA == "" {
+		if len(t.AllowedClients) != 0 {
+			return nil, errors.New("client CA is required to allow the clients")
+		}
+
+		return config, nil
+	}
+
+	pool, err := loadCertPool(t.ClientCA)
+	if err != nil {
+		return nil, err
+	}
+
+	config.ClientCAs = pool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+
+	if len(t.AllowedClients) != 0 {
+		allowed := make(map[string]struct{}, len(t.AllowedClients))
+		for _, name := range t.AllowedClients {
+			allowed[name] = struct{}{}
+		}
+
+		config.VerifyPeerCertificate = func(_ [][]byte, chains [][]*x509.Certificate) error {
+			return verifyClient(allowed, chains)
+		}
+	}
+
+	return config, nil
+}
+
+// verifyClient checks that the leaf of the verified client certificate chain is allowed by its common name or DNS name.
+func verifyClient(allowed map[string]struct{}, chains [][]*x509.Certificate) error {
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return errors.New("client certificate is not verified")
+	}
+
+	leaf := chains[0][0]
+	if _, ok := allowed[leaf.Subject.CommonName]; ok {
+		return nil
+	}
+
+	for _, name := range leaf.DNSNames {
+		if _, ok := allowed[name]; ok {
+			return nil
+		}
+	}
+
+	return errors.From(errors.New("client certificate is not allowed"), logan.F{
+		"common_name": leaf.Subject.CommonName,
+		"dns_names":   leaf.DNSNames,
+	})
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading CA bundle", logan.F{"path": path})
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.From(errors.New("no certificates found in CA bundle"), logan.F{"path": path})
+	}
+
+	return pool, nil
+}
+
+func (c *config) ListenerTLS() *tls.Config {
+	return c.listenerTLS.Do(func() interface{} {
+		var config struct {
+			TLS ListenerTLSConf `fig:"tls"`
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "listener")).Please(); err != nil {
+			panic(err)
+		}
+
+		tlsConfig, err := config.TLS.ServerConfig()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to configure listener TLS"))
+		}
+
+		return tlsConfig
+	}).(*tls.Config)
+}
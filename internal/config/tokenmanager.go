@@ -0,0 +1,38 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rarimo/sol-saver-svc/internal/service/tokenmanager"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func (c *config) TokenManager() *tokenmanager.Cache {
+	return c.tokenManager.Do(func() interface{} {
+		var config = struct {
+			// Max amount of cached responses
+			Size int `fig:"size"`
+			// Max duration of the cached response validity
+			TTL time.Duration `fig:"ttl"`
+		}{
+			Size: 10000,
+			TTL:  10 * time.Minute,
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "tokenmanager_cache")).Please(); err != nil {
+			panic(err)
+		}
+
+		if config.Size <= 0 {
+			panic(errors.New("tokenmanager_cache.size should be positive"))
+		}
+
+		if config.TTL <= 0 {
+			panic(errors.New("tokenmanager_cache.ttl should be positive"))
+		}
+
+		return tokenmanager.NewCache(c.Cosmos(), config.Size, config.TTL)
+	}).(*tokenmanager.Cache)
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type VoterConf struct {
+	// Max amount of operations verified at the same time
+	Workers int `fig:"workers"`
+	// Max duration of the single operation verification
+	Timeout time.Duration `fig:"timeout"`
+	// Max amount of operations revoted in the single batch
+	MaxBatch int `fig:"max_batch"`
+}
+
+func (c *config) VoterConf() VoterConf {
+	return c.vconf.Do(func() interface{} {
+		config := VoterConf{
+			Workers:  10,
+			Timeout:  time.Minute,
+			MaxBatch: 1000,
+		}
+
+		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "voter")).Please(); err != nil {
+			panic(err)
+		}
+
+		if config.Workers <= 0 {
+			panic(errors.New("voter.workers should be positive"))
+		}
+
+		if config.Timeout <= 0 {
+			panic(errors.New("voter.timeout should be positive"))
+		}
+
+		if config.MaxBatch <= 0 {
+			panic(errors.New("voter.max_batch should be positive"))
+		}
+
+		return config
+	}).(VoterConf)
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"strings"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Role is the access level required to call the gRPC method. Every role includes the lower ones.
+type Role int
+
+const (
+	// RolePublic methods can be called without credentials
+	RolePublic Role = iota
+	// RoleRead allows the read-only queries
+	RoleRead
+	// RoleAdmin allows the actions changing the service or core state
+	RoleAdmin
+)
+
+var roles = map[string]Role{
+	"read":  RoleRead,
+	"admin": RoleAdmin,
+}
+
+func ParseRole(role string) (Role, error) {
+	if r, ok := roles[role]; ok {
+		return r, nil
+	}
+
+	return RolePublic, errors.From(errors.New("unknown role"), logan.F{"role": role})
+}
+
+func (r Role) String() string {
+	switch r {
+	case RolePublic:
+		return "public"
+	case RoleRead:
+		return "read"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+// Identity is the authenticated caller.
+type Identity struct {
+	Name string
+	Role Role
+}
+
+// Authorizer authenticates the gRPC callers by the bearer token or by the verified mTLS client certificate
+// (common name or DNS name) and checks their roles against the roles required by the methods.
+// Methods without the required role are available to admins only. Denied calls are audit-logged.
+type Authorizer struct {
+	log     *logan.Entry
+	tokens  map[[sha256.Size]byte]Identity
+	clients map[string]Identity
+}
+
+func NewAuthorizer(log *logan.Entry, tokens map[string]Identity, clients map[string]Identity) *Authorizer {
+	a := &Authorizer{
+		log:     log,
+		tokens:  make(map[[sha256.Size]byte]Identity, len(tokens)),
+		clients: clients,
+	}
+
+	// Only token hashes are kept, so the lookup time does not depend on the matching token prefix
+	for token, identity := range tokens {
+		a.tokens[sha256.Sum256([]byte(token))] = identity
+	}
+
+	return a
+}
+
+func (a *Authorizer) UnaryInterceptor(methods map[string]Role) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod, methods); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authorizer) StreamInterceptor(methods map[string]Role) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(stream.Context(), info.FullMethod, methods); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+func (a *Authorizer) authorize(ctx context.Context, method string, methods map[string]Role) error {
+	required, ok := methods[method]
+	if !ok {
+		required = RoleAdmin
+	}
+
+	if required == RolePublic {
+		return nil
+	}
+
+	log := a.log.WithFields(logan.F{"method": method, "required_role": required.String()})
+	if p, ok := peer.FromContext(ctx); ok {
+		log = log.WithField("peer", p.Addr.String())
+	}
+
+	identity, err := a.authenticate(ctx)
+	if err != nil {
+		log.WithField("reason", err.Error()).Warn("gRPC call denied: unauthenticated")
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	log = log.WithFields(logan.F{"identity": identity.Name, "role": identity.Role.String()})
+
+	if identity.Role < required {
+		log.Warn("gRPC call denied: insufficient role")
+		return status.Error(codes.PermissionDenied, "insufficient role")
+	}
+
+	if required == RoleAdmin {
+		log.Info("Admin gRPC call")
+	}
+
+	return nil
+}
+
+// authenticate returns the identity of the bearer token if it is provided, otherwise the identity of the client certificate.
+func (a *Authorizer) authenticate(ctx context.Context) (Identity, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) != 0 {
+			if !strings.HasPrefix(values[0], "Bearer ") {
+				return Identity{}, errors.New("invalid authorization header")
+			}
+
+			token := strings.TrimPrefix(values[0], "Bearer ")
+			identity, ok := a.tokens[sha256.Sum256([]byte(token))]
+			if !ok {
+				return Identity{}, errors.New("invalid token")
+			}
+
+			return identity, nil
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, errors.New("credentials are required")
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return Identity{}, errors.New("credentials are required")
+	}
+
+	leaf := info.State.VerifiedChains[0][0]
+	if identity, ok := a.clients[leaf.Subject.CommonName]; ok {
+		return identity, nil
+	}
+
+	for _, name := range leaf.DNSNames {
+		if identity, ok := a.clients[name]; ok {
+			return identity, nil
+		}
+	}
+
+	return Identity{}, errors.From(errors.New("unknown client certificate"), logan.F{"common_name": leaf.Subject.CommonName})
+}
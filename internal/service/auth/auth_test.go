@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	publicMethod = "/test.Service/Public"
+	readMethod   = "/test.Service/Read"
+	adminMethod  = "/test.Service/Admin"
+	// not listed in the methods, admin is required
+	unknownMethod = "/test.Service/Unknown"
+)
+
+var methods = map[string]Role{
+	publicMethod: RolePublic,
+	readMethod:   RoleRead,
+	adminMethod:  RoleAdmin,
+}
+
+func TestParseRole(t *testing.T) {
+	cases := []struct {
+		role    string
+		want    Role
+		wantErr bool
+	}{
+		{role: "read", want: RoleRead},
+		{role: "admin", want: RoleAdmin},
+		{role: "public", wantErr: true},
+		{role: "Admin", wantErr: true},
+		{role: "", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.role, func(t *testing.T) {
+			role, err := ParseRole(c.role)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got role %s", role)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if role != c.want {
+				t.Fatalf("expected role %s, got %s", c.want, role)
+			}
+		})
+	}
+}
+
+func withToken(ctx context.Context, header string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", header))
+}
+
+func withPeer(ctx context.Context, auth credentials.AuthInfo) context.Context {
+	return peer.NewContext(ctx, &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234},
+		AuthInfo: auth,
+	})
+}
+
+func withClientCert(ctx context.Context, commonName string, dnsNames ...string) context.Context {
+	leaf := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}, DNSNames: dnsNames}
+
+	return withPeer(ctx, credentials.TLSInfo{State: tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{leaf},
+		VerifiedChains:   [][]*x509.Certificate{{leaf}},
+	}})
+}
+
+func TestAuthorize(t *testing.T) {
+	authorizer := NewAuthorizer(logan.New(), map[string]Identity{
+		"read-token":  {Name: "reader", Role: RoleRead},
+		"admin-token": {Name: "operator", Role: RoleAdmin},
+	}, map[string]Identity{
+		"monitoring":       {Name: "monitoring", Role: RoleRead},
+		"admin.example.io": {Name: "admin-client", Role: RoleAdmin},
+	})
+
+	unverified := withPeer(context.Background(), credentials.TLSInfo{State: tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "admin.example.io"}}},
+	}})
+
+	cases := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		want   codes.Code
+	}{
+		{name: "public without credentials", ctx: context.Background(), method: publicMethod, want: codes.OK},
+		{name: "read without credentials", ctx: context.Background(), method: readMethod, want: codes.Unauthenticated},
+		{name: "read without TLS peer", ctx: withPeer(context.Background(), nil), method: readMethod, want: codes.Unauthenticated},
+
+		{name: "read token on read method", ctx: withToken(context.Background(), "Bearer read-token"), method: readMethod, want: codes.OK},
+		{name: "read token on admin method", ctx: withToken(context.Background(), "Bearer read-token"), method: adminMethod, want: codes.PermissionDenied},
+		{name: "admin token on admin method", ctx: withToken(context.Background(), "Bearer admin-token"), method: adminMethod, want: codes.OK},
+		{name: "admin token on read method", ctx: withToken(context.Background(), "Bearer admin-token"), method: readMethod, want: codes.OK},
+		{name: "unknown token", ctx: withToken(context.Background(), "Bearer other-token"), method: readMethod, want: codes.Unauthenticated},
+		{name: "token without bearer scheme", ctx: withToken(context.Background(), "admin-token"), method: readMethod, want: codes.Unauthenticated},
+		{name: "token takes precedence over certificate", ctx: withToken(withClientCert(context.Background(), "admin.example.io"), "Bearer read-token"), method: adminMethod, want: codes.PermissionDenied},
+
+		{name: "client common name", ctx: withClientCert(context.Background(), "monitoring"), method: readMethod, want: codes.OK},
+		{name: "client common name insufficient role", ctx: withClientCert(context.Background(), "monitoring"), method: adminMethod, want: codes.PermissionDenied},
+		{name: "client DNS name", ctx: withClientCert(context.Background(), "unknown", "other.example.io", "admin.example.io"), method: adminMethod, want: codes.OK},
+		{name: "unknown client", ctx: withClientCert(context.Background(), "unknown", "other.example.io"), method: readMethod, want: codes.Unauthenticated},
+		{name: "unverified client certificate", ctx: unverified, method: readMethod, want: codes.Unauthenticated},
+
+		{name: "unknown method requires admin for reader", ctx: withToken(context.Background(), "Bearer read-token"), method: unknownMethod, want: codes.PermissionDenied},
+		{name: "unknown method allowed for admin", ctx: withToken(context.Background(), "Bearer admin-token"), method: unknownMethod, want: codes.OK},
+		{name: "unknown method without credentials", ctx: context.Background(), method: unknownMethod, want: codes.Unauthenticated},
+	}
+
+	unary := authorizer.UnaryInterceptor(methods)
+	stream := authorizer.StreamInterceptor(methods)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := unary(c.ctx, nil, &grpc.UnaryServerInfo{FullMethod: c.method}, func(context.Context, interface{}) (interface{}, error) {
+				return nil, nil
+			})
+			if code := status.Code(err); code != c.want {
+				t.Fatalf("unary: expected %s, got %s (%v)", c.want, code, err)
+			}
+
+			err = stream(nil, &serverStream{ctx: c.ctx}, &grpc.StreamServerInfo{FullMethod: c.method}, func(interface{}, grpc.ServerStream) error {
+				return nil
+			})
+			if code := status.Code(err); code != c.want {
+				t.Fatalf("stream: expected %s, got %s (%v)", c.want, code, err)
+			}
+		})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
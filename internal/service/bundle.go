@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// bundleArguments is the ABI layout of the bundle executed by the bridge bundler on EVM chains:
+// abi.encode(address[] targets, uint256[] values, bytes[] data)
+var bundleArguments = mustBundleArguments()
+
+func mustBundleArguments() abi.Arguments {
+	var arguments abi.Arguments
+	for _, t := range []string{"address[]", "uint256[]", "bytes[]"} {
+		typ, err := abi.NewType(t, "", nil)
+		if err != nil {
+			panic(err)
+		}
+
+		arguments = append(arguments, abi.Argument{Type: typ})
+	}
+
+	return arguments
+}
+
+// BundleCall is the single call of the cross-chain bundle.
+type BundleCall struct {
+	Target common.Address `json:"target"`
+	Value  *big.Int       `json:"value"`
+	Data   hexutil.Bytes  `json:"data"`
+}
+
+// Bundle is the decoded list of calls executed on the target chain after the transfer.
+type Bundle struct {
+	Calls []BundleCall `json:"calls"`
+}
+
+func (b *Bundle) String() string {
+	calls := make([]string, 0, len(b.Calls))
+	for _, call := range b.Calls {
+		calls = append(calls, fmt.Sprintf("%s{value: %s, data: %s}", call.Target.Hex(), call.Value.String(), call.Data.String()))
+	}
+
+	return "[" + strings.Join(calls, ", ") + "]"
+}
+
+// DecodeBundle decodes the ABI encoded bundle. Bundle should be canonically encoded
+// and contain the same amount of targets, values and call data.
+func DecodeBundle(data []byte) (*Bundle, error) {
+	values, err := bundleArguments.Unpack(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unpacking bundle")
+	}
+
+	targets := values[0].([]common.Address)
+	amounts := values[1].([]*big.Int)
+	calldata := values[2].([][]byte)
+
+	if len(targets) != len(amounts) || len(targets) != len(calldata) {
+		return nil, errors.New("bundle targets, values and data lengths mismatch")
+	}
+
+	// rejecting trailing or non-canonical data that is ignored by the decoder
+	encoded, err := bundleArguments.Pack(targets, amounts, calldata)
+	if err != nil {
+		return nil, errors.Wrap(err, "error packing bundle")
+	}
+
+	if !bytes.Equal(encoded, data) {
+		return nil, errors.New("bundle is not canonically encoded")
+	}
+
+	bundle := &Bundle{Calls: make([]BundleCall, 0, len(targets))}
+	for i := range targets {
+		bundle.Calls = append(bundle.Calls, BundleCall{
+			Target: targets[i],
+			Value:  amounts[i],
+			Data:   calldata[i],
+		})
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	lib "github.com/rarimo/saver-grpc-lib/grpc"
+	"github.com/rarimo/sol-saver-svc/api"
+	"github.com/rarimo/sol-saver-svc/internal/config"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// Service serves the HTTP/JSON gateway to the service gRPC listener.
+// Requests are proxied through the gRPC listener, so the same authorization rules are applied:
+// `Authorization` header is forwarded as the gRPC metadata.
+type Service struct {
+	log      *logan.Entry
+	addr     string
+	grpcAddr string
+	tls      *tls.Config
+	grpcTLS  *tls.Config
+}
+
+func NewService(cfg config.Config) *Service {
+	return &Service{
+		log:      cfg.Log(),
+		addr:     cfg.GatewayConf().Addr,
+		grpcAddr: cfg.Listener().Addr().String(),
+		tls:      cfg.GatewayConf().TLS,
+		grpcTLS:  cfg.GatewayConf().GRPCTLS,
+	}
+}
+
+func (s *Service) Run(ctx context.Context) {
+	if err := s.run(ctx); err != nil {
+		s.log.WithError(err).Error("HTTP/JSON gateway failed")
+	}
+}
+
+func (s *Service) run(ctx context.Context) error {
+	creds := insecure.NewCredentials()
+	if s.grpcTLS != nil {
+		creds = credentials.NewTLS(s.grpcTLS)
+	}
+
+	conn, err := grpc.DialContext(ctx, s.grpcAddr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return errors.Wrap(err, "error dialing gRPC listener", logan.F{"addr": s.grpcAddr})
+	}
+	defer conn.Close()
+
+	mux := runtime.NewServeMux()
+
+	if err := api.RegisterDepositsHandler(ctx, mux, conn); err != nil {
+		return errors.Wrap(err, "error registering deposits handler")
+	}
+
+	if err := api.RegisterAdminHandler(ctx, mux, conn); err != nil {
+		return errors.Wrap(err, "error registering admin handler")
+	}
+
+	if err := mux.HandlePath(http.MethodPost, "/v1/revote", revoteHandler(mux, lib.NewSaverClient(conn))); err != nil {
+		return errors.Wrap(err, "error registering revote handler")
+	}
+
+	if err := mux.HandlePath(http.MethodGet, "/openapi.json", openAPIHandler); err != nil {
+		return errors.Wrap(err, "error registering OpenAPI handler")
+	}
+
+	server := &http.Server{
+		Addr:      s.addr,
+		Handler:   mux,
+		TLSConfig: s.tls,
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Close(); err != nil {
+			s.log.WithError(err).Error("error closing gateway")
+		}
+	}()
+
+	s.log.WithField("addr", s.addr).Info("Serving HTTP/JSON gateway")
+
+	if s.tls != nil {
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return err
+}
+
+// revoteHandler proxies the Saver/Revote method that has no generated gateway handler
+// because saver-grpc-lib types are not compatible with the gateway runtime.
+func revoteHandler(mux *runtime.ServeMux, client lib.SaverClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, "/Saver/Revote", runtime.WithHTTPPathPattern("/v1/revote"))
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+			return
+		}
+
+		var req lib.RevoteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+
+		resp, err := client.Revote(ctx, &req)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Error(codes.Internal, err.Error()))
+		}
+	}
+}
+
+func openAPIHandler(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(api.OpenAPI)
+}
@@ -0,0 +1,292 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/olegfomenko/solana-go"
+	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/api"
+	"github.com/rarimo/sol-saver-svc/internal/service/pause"
+	"github.com/rarimo/sol-saver-svc/internal/service/saver/catchup"
+	"github.com/rarimo/sol-saver-svc/internal/service/voter"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var catchupStatuses = map[catchup.JobStatus]api.CatchupStatus{
+	catchup.JobRunning:   api.CatchupStatus_CATCHUP_STATUS_RUNNING,
+	catchup.JobDone:      api.CatchupStatus_CATCHUP_STATUS_DONE,
+	catchup.JobFailed:    api.CatchupStatus_CATCHUP_STATUS_FAILED,
+	catchup.JobCancelled: api.CatchupStatus_CATCHUP_STATUS_CANCELLED,
+}
+
+var votes = map[rarimotypes.VoteType]api.Vote{
+	rarimotypes.VoteType_YES: api.Vote_VOTE_YES,
+	rarimotypes.VoteType_NO:  api.Vote_VOTE_NO,
+}
+
+// errNoCatchup is returned for catchup requests to the service that does not run the saver.
+var errNoCatchup = status.Error(codes.Unimplemented, "catchup is available only in the full mode")
+
+type AdminService struct {
+	api.UnimplementedAdminServer
+	log     *logan.Entry
+	jobs    *catchup.Jobs
+	revoter *voter.Revoter
+	pause   *pause.Switch
+	pending *pause.Pending
+}
+
+// NewAdminService creates the admin service, jobs are nil if the service does not run the saver.
+func NewAdminService(log *logan.Entry, jobs *catchup.Jobs, revoter *voter.Revoter, pause *pause.Switch, pending *pause.Pending) *AdminService {
+	return &AdminService{
+		log:     log,
+		jobs:    jobs,
+		revoter: revoter,
+		pause:   pause,
+		pending: pending,
+	}
+}
+
+var _ api.AdminServer = &AdminService{}
+
+func (a *AdminService) StartCatchup(_ context.Context, req *api.StartCatchupRequest) (*api.CatchupJob, error) {
+	if a.jobs == nil {
+		return nil, errNoCatchup
+	}
+
+	var r catchup.Range
+
+	switch rng := req.Range.(type) {
+	case *api.StartCatchupRequest_FromTx:
+		sig, err := solana.SignatureFromBase58(rng.FromTx)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid transaction signature")
+		}
+
+		r.FromTx = sig
+	case *api.StartCatchupRequest_Slots:
+		if rng.Slots.ToSlot != 0 && rng.Slots.FromSlot > rng.Slots.ToSlot {
+			return nil, status.Error(codes.InvalidArgument, "from slot is greater than to slot")
+		}
+
+		r.FromSlot = rng.Slots.FromSlot
+		r.ToSlot = rng.Slots.ToSlot
+	default:
+		return nil, status.Error(codes.InvalidArgument, "catchup range is required")
+	}
+
+	job, err := a.jobs.Start(r)
+	if err != nil {
+		if errors.Cause(err) == catchup.ErrJobRunning {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+
+		a.log.WithError(err).Error("error starting catchup job")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	return toCatchupJob(job), nil
+}
+
+func (a *AdminService) GetCatchup(_ context.Context, req *api.GetCatchupRequest) (*api.CatchupJob, error) {
+	if a.jobs == nil {
+		return nil, errNoCatchup
+	}
+
+	job, err := a.jobs.Get(req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return toCatchupJob(job), nil
+}
+
+func (a *AdminService) CancelCatchup(_ context.Context, req *api.CancelCatchupRequest) (*api.CatchupJob, error) {
+	if a.jobs == nil {
+		return nil, errNoCatchup
+	}
+
+	job, err := a.jobs.Cancel(req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	a.log.WithField("catchup_job", job.ID).Info("Catchup job cancel requested")
+	return toCatchupJob(job), nil
+}
+
+func (a *AdminService) BatchRevote(req *api.BatchRevoteRequest, stream api.Admin_BatchRevoteServer) error {
+	var indexes []string
+
+	switch ops := req.Operations.(type) {
+	case *api.BatchRevoteRequest_Indexes:
+		if len(ops.Indexes.Indexes) == 0 {
+			return status.Error(codes.InvalidArgument, "operation indexes are required")
+		}
+
+		indexes = dedupe(ops.Indexes.Indexes)
+		if len(indexes) > a.revoter.MaxBatch() {
+			return status.Errorf(codes.InvalidArgument, "too many operations, at most %d can be revoted in the single batch", a.revoter.MaxBatch())
+		}
+	case *api.BatchRevoteRequest_Blocks:
+		if ops.Blocks.FromHeight <= 0 {
+			return status.Error(codes.InvalidArgument, "from height should be positive")
+		}
+
+		if ops.Blocks.ToHeight != 0 && ops.Blocks.FromHeight > ops.Blocks.ToHeight {
+			return status.Error(codes.InvalidArgument, "from height is greater than to height")
+		}
+
+		var err error
+		indexes, err = a.revoter.Find(stream.Context(), voter.RevoteFilter{
+			FromHeight: ops.Blocks.FromHeight,
+			ToHeight:   ops.Blocks.ToHeight,
+		})
+		if errors.Cause(err) == voter.ErrTooManyOperations {
+			return status.Errorf(codes.InvalidArgument, "too many unvoted operations in the blocks range, at most %d can be revoted in the single batch", a.revoter.MaxBatch())
+		}
+
+		if err != nil {
+			a.log.WithError(err).Error("error finding operations to revote")
+			return status.Error(codes.Internal, "Internal error")
+		}
+	default:
+		return status.Error(codes.InvalidArgument, "operations are required")
+	}
+
+	a.log.WithField("operations", len(indexes)).Info("Batch revote started")
+
+	var processed uint32
+	for result := range a.revoter.Revote(stream.Context(), indexes) {
+		processed++
+		logRevoteError(a.log, result)
+
+		if err := stream.Send(toRevoteResult(result, processed, uint32(len(indexes)))); err != nil {
+			// Revote is stopped by the stream context cancellation
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *AdminService) GetPause(context.Context, *api.GetPauseRequest) (*api.PauseState, error) {
+	return a.toPauseState(a.pause.State())
+}
+
+func (a *AdminService) SetPause(_ context.Context, req *api.SetPauseRequest) (*api.PauseState, error) {
+	state := a.pause.State()
+
+	switches := map[pause.Component]*bool{
+		pause.Saver: req.Saver,
+		pause.Voter: req.Voter,
+	}
+
+	for component, paused := range switches {
+		if paused == nil {
+			continue
+		}
+
+		var err error
+		if state, err = a.pause.Set(component, *paused); err != nil {
+			a.log.WithError(err).Error("error setting pause state")
+			return nil, status.Error(codes.Internal, "Internal error")
+		}
+	}
+
+	return a.toPauseState(state)
+}
+
+func (a *AdminService) toPauseState(state pause.State) (*api.PauseState, error) {
+	pending, err := a.pending.List()
+	if err != nil {
+		a.log.WithError(err).Error("error loading pending deposits")
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	result := &api.PauseState{
+		Saver:           state.Saver,
+		Voter:           state.Voter,
+		PendingDeposits: uint32(len(pending)),
+	}
+
+	if !state.UpdatedAt.IsZero() {
+		result.UpdatedAt = timestamppb.New(state.UpdatedAt)
+	}
+
+	return result, nil
+}
+
+func toRevoteResult(result voter.RevoteResult, processed, total uint32) *api.RevoteResult {
+	resp := &api.RevoteResult{
+		Index:     result.Index,
+		Status:    api.RevoteStatus_REVOTE_STATUS_VOTED,
+		Processed: processed,
+		Total:     total,
+	}
+
+	switch errors.Cause(result.Err) {
+	case nil:
+		resp.Vote = votes[result.Vote]
+		return resp
+	case verifiers.ErrWrongOperationContent:
+		// NO vote is broadcasted, error is the reason
+		resp.Vote = votes[result.Vote]
+	case voter.ErrOperationProcessed, voter.ErrOperationVoted:
+		resp.Status = api.RevoteStatus_REVOTE_STATUS_SKIPPED
+	default:
+		resp.Status = api.RevoteStatus_REVOTE_STATUS_FAILED
+	}
+
+	resp.Error = revoteMessage(result)
+	return resp
+}
+
+func dedupe(indexes []string) []string {
+	seen := make(map[string]struct{}, len(indexes))
+	result := make([]string, 0, len(indexes))
+
+	for _, index := range indexes {
+		if _, ok := seen[index]; ok {
+			continue
+		}
+
+		seen[index] = struct{}{}
+		result = append(result, index)
+	}
+
+	return result
+}
+
+func toCatchupJob(job catchup.Job) *api.CatchupJob {
+	result := &api.CatchupJob{
+		Id:           job.ID,
+		Status:       catchupStatuses[job.Status],
+		Pages:        job.Progress.Pages,
+		Transactions: job.Progress.Transactions,
+		Deposits:     job.Progress.Deposits,
+		Broadcasted:  job.Progress.Broadcasted,
+		StartedAt:    timestamppb.New(job.StartedAt),
+	}
+
+	if job.Range.FromTx.IsZero() {
+		result.Slots = &api.SlotRange{FromSlot: job.Range.FromSlot, ToSlot: job.Range.ToSlot}
+	} else {
+		result.FromTx = job.Range.FromTx.String()
+	}
+
+	if job.Err != nil {
+		result.Error = job.Err.Error()
+	}
+
+	if !job.FinishedAt.IsZero() {
+		result.FinishedAt = timestamppb.New(job.FinishedAt)
+	}
+
+	return result
+}
@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	lib "github.com/rarimo/saver-grpc-lib/grpc"
+	"github.com/rarimo/sol-saver-svc/api"
+	"github.com/rarimo/sol-saver-svc/internal/service/auth"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// methodRoles are the roles required to call the service methods.
+// Methods missing here are available to admins only.
+var methodRoles = map[string]auth.Role{
+	fullMethod(healthpb.Health_ServiceDesc, "Check"): auth.RolePublic,
+	fullMethod(healthpb.Health_ServiceDesc, "Watch"): auth.RolePublic,
+
+	fullMethod(reflectionpb.ServerReflection_ServiceDesc, "ServerReflectionInfo"): auth.RoleRead,
+	fullMethod(api.Deposits_ServiceDesc, "GetDeposits"):                           auth.RoleRead,
+	fullMethod(api.Deposits_ServiceDesc, "SubscribeDeposits"):                     auth.RoleRead,
+	fullMethod(api.Admin_ServiceDesc, "GetCatchup"):                               auth.RoleRead,
+	fullMethod(api.Admin_ServiceDesc, "GetPause"):                                 auth.RoleRead,
+
+	fullMethod(lib.Saver_ServiceDesc, "Revote"):        auth.RoleAdmin,
+	fullMethod(api.Admin_ServiceDesc, "StartCatchup"):  auth.RoleAdmin,
+	fullMethod(api.Admin_ServiceDesc, "CancelCatchup"): auth.RoleAdmin,
+	fullMethod(api.Admin_ServiceDesc, "BatchRevote"):   auth.RoleAdmin,
+}
+
+func fullMethod(desc grpc.ServiceDesc, method string) string {
+	return "/" + desc.ServiceName + "/" + method
+}
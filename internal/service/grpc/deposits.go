@@ -0,0 +1,285 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"github.com/rarimo/sol-saver-svc/api"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/sol-saver-svc/internal/service/saver"
+	"github.com/rarimo/solana-program-go/contracts/bridge"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var instructions = map[bridge.Instruction]api.Instruction{
+	bridge.InstructionDepositNative: api.Instruction_INSTRUCTION_DEPOSIT_NATIVE,
+	bridge.InstructionDepositFT:     api.Instruction_INSTRUCTION_DEPOSIT_FT,
+	bridge.InstructionDepositNFT:    api.Instruction_INSTRUCTION_DEPOSIT_NFT,
+}
+
+var depositStatuses = map[saver.DepositStatus]api.DepositStatus{
+	saver.DepositDecoded:  api.DepositStatus_DEPOSIT_STATUS_DECODED,
+	saver.DepositRejected: api.DepositStatus_DEPOSIT_STATUS_REJECTED,
+	saver.DepositRefund:   api.DepositStatus_DEPOSIT_STATUS_REFUND,
+	saver.DepositFailed:   api.DepositStatus_DEPOSIT_STATUS_FAILED,
+}
+
+type DepositsService struct {
+	api.UnimplementedDepositsServer
+	log     *logan.Entry
+	solana  *rpc.Client
+	decoder *saver.Decoder
+	feed    *saver.Feed
+	rarimo  *grpc.ClientConn
+	chain   string
+}
+
+func NewDepositsService(log *logan.Entry, solana *rpc.Client, decoder *saver.Decoder, feed *saver.Feed, rarimo *grpc.ClientConn, chain string) *DepositsService {
+	return &DepositsService{
+		log:     log,
+		solana:  solana,
+		decoder: decoder,
+		feed:    feed,
+		rarimo:  rarimo,
+		chain:   chain,
+	}
+}
+
+var _ api.DepositsServer = &DepositsService{}
+
+func (d *DepositsService) GetDeposits(ctx context.Context, req *api.GetDepositsRequest) (*api.GetDepositsResponse, error) {
+	sig, err := solana.SignatureFromBase58(req.Tx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid transaction signature")
+	}
+
+	tx, err := service.GetTransaction(ctx, d.solana, sig)
+	if err != nil {
+		if errors.Cause(err) == rpc.ErrNotFound {
+			return nil, status.Error(codes.NotFound, "transaction not found")
+		}
+
+		d.log.WithError(err).Error("error getting transaction")
+		return nil, status.Error(codes.Unavailable, "error getting transaction from Solana")
+	}
+
+	if tx == nil {
+		return nil, status.Error(codes.FailedPrecondition, "transaction has failed")
+	}
+
+	deposits := d.decoder.DecodeTransaction(ctx, sig, tx)
+
+	resp := &api.GetDepositsResponse{}
+
+	for _, deposit := range deposits {
+		if req.EventId != nil && uint32(deposit.EventId) != *req.EventId {
+			continue
+		}
+
+		result, err := d.toResponse(ctx, sig, deposit)
+		if err != nil {
+			d.log.WithError(err).Error("error getting deposit operation")
+			return nil, status.Error(codes.Unavailable, "error getting operation from core")
+		}
+
+		resp.Deposits = append(resp.Deposits, result)
+	}
+
+	if req.EventId != nil && len(resp.Deposits) == 0 {
+		return nil, status.Error(codes.NotFound, "deposit not found")
+	}
+
+	return resp, nil
+}
+
+func (d *DepositsService) SubscribeDeposits(req *api.SubscribeDepositsRequest, stream api.Deposits_SubscribeDepositsServer) error {
+	// Deposits are published to the feed by the saver listener only
+	if d.feed == nil {
+		return status.Error(codes.Unimplemented, "deposits feed is available only in the full mode")
+	}
+
+	seq := d.feed.Head()
+
+	if req.Cursor != nil {
+		sig, err := solana.SignatureFromBase58(req.Cursor.Tx)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid cursor transaction signature")
+		}
+
+		cursor := saver.FeedCursor{Slot: req.Cursor.Slot, Tx: sig, Seq: req.Cursor.Seq}
+		if req.Cursor.EventId != nil {
+			eventId := int(*req.Cursor.EventId)
+			cursor.EventId = &eventId
+		}
+
+		if seq, err = d.feed.Find(cursor); err != nil {
+			return status.Error(codes.OutOfRange, "cursor is not found in the feed")
+		}
+	}
+
+	for {
+		events, next, err := d.feed.After(seq)
+		if err != nil {
+			return status.Error(codes.OutOfRange, "deposits were evicted from the feed, resubscribe with the last cursor")
+		}
+
+		for _, event := range events {
+			if err := stream.Send(d.toEvent(event)); err != nil {
+				return err
+			}
+
+			seq = event.Seq
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-next:
+		}
+	}
+}
+
+func (d *DepositsService) toEvent(event saver.FeedEvent) *api.DepositEvent {
+	eventId := uint32(event.Deposit.EventId)
+	result := &api.DepositEvent{
+		Cursor: &api.Cursor{
+			Slot:    event.Slot,
+			Tx:      event.Tx.String(),
+			EventId: &eventId,
+			Seq:     event.Seq,
+		},
+		Deposit: d.toDeposit(event.Tx, event.Deposit),
+	}
+
+	result.Deposit.Operation = &api.Operation{Index: d.operationIndex(event.Tx, event.Deposit.EventId)}
+
+	if event.Deposit.Status == saver.DepositDecoded {
+		result.Broadcast = &api.Broadcast{Success: event.BroadcastErr == nil && !event.Pending, Pending: event.Pending}
+		if event.BroadcastErr != nil {
+			result.Broadcast.Error = event.BroadcastErr.Error()
+		}
+	}
+
+	return result
+}
+
+func (d *DepositsService) toResponse(ctx context.Context, sig solana.Signature, deposit saver.Deposit) (*api.Deposit, error) {
+	result := d.toDeposit(sig, deposit)
+
+	operation, err := d.getOperation(ctx, sig, deposit.EventId)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Operation = operation
+	return result, nil
+}
+
+func (d *DepositsService) toDeposit(sig solana.Signature, deposit saver.Deposit) *api.Deposit {
+	result := &api.Deposit{
+		Tx:           sig.String(),
+		EventId:      uint32(deposit.EventId),
+		Instruction:  instructions[deposit.Instruction],
+		Status:       depositStatuses[deposit.Status],
+		RefundReason: deposit.RefundReason,
+	}
+
+	if deposit.Err != nil {
+		result.Error = deposit.Err.Error()
+	}
+
+	msg := deposit.Msg
+	if msg == nil {
+		return result
+	}
+
+	result.Sender = msg.Sender
+	result.Receiver = msg.Receiver
+	result.Amount = msg.Amount
+	result.From = toIndex(&msg.From)
+	result.To = toIndex(&msg.To)
+
+	if msg.Meta != nil {
+		result.NftMetadata = &api.NFTMetadata{
+			ImageUri:  msg.Meta.ImageUri,
+			ImageHash: msg.Meta.ImageHash,
+			Seed:      msg.Meta.Seed,
+			Uri:       msg.Meta.Uri,
+		}
+	}
+
+	if msg.BundleData != "" {
+		result.Bundle = toBundle(msg.BundleData, msg.BundleSalt)
+	}
+
+	return result
+}
+
+// operationIndex returns the index of the deposit transfer operation: HASH(tx, event, chain), the same as calculated by core.
+func (d *DepositsService) operationIndex(sig solana.Signature, eventId int) string {
+	return hexutil.Encode(crypto.Keccak256([]byte(sig.String()), []byte(fmt.Sprint(eventId)), []byte(d.chain)))
+}
+
+// getOperation returns the core transfer operation of the deposit.
+func (d *DepositsService) getOperation(ctx context.Context, sig solana.Signature, eventId int) (*api.Operation, error) {
+	index := d.operationIndex(sig, eventId)
+	operation := &api.Operation{Index: index}
+
+	resp, err := rarimotypes.NewQueryClient(d.rarimo).Operation(ctx, &rarimotypes.QueryGetOperationRequest{Index: index})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return operation, nil
+		}
+
+		return nil, errors.Wrap(err, "error fetching operation", logan.F{"index": index})
+	}
+
+	operation.Found = true
+	operation.Status = resp.Operation.Status.String()
+	return operation, nil
+}
+
+func toIndex(index *tokentypes.OnChainItemIndex) *api.OnChainItemIndex {
+	return &api.OnChainItemIndex{
+		Chain:   index.Chain,
+		Address: index.Address,
+		TokenId: index.TokenID,
+	}
+}
+
+func toBundle(data, salt string) *api.Bundle {
+	bundle := &api.Bundle{
+		Data: data,
+		Salt: salt,
+	}
+
+	raw, err := hexutil.Decode(data)
+	if err != nil {
+		return bundle
+	}
+
+	decoded, err := service.DecodeBundle(raw)
+	if err != nil {
+		return bundle
+	}
+
+	for _, call := range decoded.Calls {
+		bundle.Calls = append(bundle.Calls, &api.BundleCall{
+			Target: call.Target.Hex(),
+			Value:  call.Value.String(),
+			Data:   call.Data.String(),
+		})
+	}
+
+	return bundle
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/olegfomenko/solana-go"
+	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
+	"github.com/rarimo/sol-saver-svc/internal/service/saver"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func TestToEventBroadcast(t *testing.T) {
+	decoded := saver.Deposit{Status: saver.DepositDecoded, Msg: &oracletypes.MsgCreateTransferOp{}}
+
+	cases := []struct {
+		name        string
+		event       saver.FeedEvent
+		noBroadcast bool
+		success     bool
+		pending     bool
+		err         string
+	}{
+		{name: "broadcasted", event: saver.FeedEvent{Deposit: decoded}, success: true},
+		{name: "broadcast failed", event: saver.FeedEvent{Deposit: decoded, BroadcastErr: errors.New("core is down")}, err: "core is down"},
+		{name: "kept pending", event: saver.FeedEvent{Deposit: decoded, Pending: true}, pending: true},
+		{name: "not decoded", event: saver.FeedEvent{Deposit: saver.Deposit{Status: saver.DepositRejected}}, noBroadcast: true},
+	}
+
+	d := &DepositsService{chain: "Solana"}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.event.Tx = solana.Signature{1}
+			c.event.Seq = 7
+
+			event := d.toEvent(c.event)
+			if event.Cursor.Seq != 7 || event.Cursor.EventId == nil || *event.Cursor.EventId != 0 {
+				t.Fatalf("unexpected cursor %v", event.Cursor)
+			}
+
+			if c.noBroadcast {
+				if event.Broadcast != nil {
+					t.Fatalf("unexpected broadcast %v", event.Broadcast)
+				}
+				return
+			}
+
+			if event.Broadcast.Success != c.success || event.Broadcast.Pending != c.pending || event.Broadcast.Error != c.err {
+				t.Fatalf("unexpected broadcast %v", event.Broadcast)
+			}
+		})
+	}
+}
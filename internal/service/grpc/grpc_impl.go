@@ -2,14 +2,20 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 
-	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	lib "github.com/rarimo/saver-grpc-lib/grpc"
-	"github.com/rarimo/saver-grpc-lib/voter"
+	"github.com/rarimo/sol-saver-svc/api"
+	"github.com/rarimo/sol-saver-svc/internal/service/auth"
+	"github.com/rarimo/sol-saver-svc/internal/service/health"
+	"github.com/rarimo/sol-saver-svc/internal/service/voter"
 	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 )
 
@@ -17,39 +23,70 @@ type SaverService struct {
 	lib.UnimplementedSaverServer
 	log      *logan.Entry
 	listener net.Listener
-	voter    *voter.Voter
-	rarimo   *grpc.ClientConn
+	tls      *tls.Config
+	auth     *auth.Authorizer
+	revoter  *voter.Revoter
+	health   *health.Monitor
+	deposits *DepositsService
+	admin    *AdminService
 }
 
-func NewSaverService(log *logan.Entry, listener net.Listener, voter *voter.Voter, rarimo *grpc.ClientConn) *SaverService {
+// NewSaverService creates the gRPC service. Listener serves plaintext connections if tls is <nil>,
+// all methods are available to any caller if auth is <nil>.
+func NewSaverService(log *logan.Entry, listener net.Listener, tls *tls.Config, auth *auth.Authorizer, revoter *voter.Revoter, health *health.Monitor, deposits *DepositsService, admin *AdminService) *SaverService {
 	return &SaverService{
 		log:      log,
 		listener: listener,
-		voter:    voter,
-		rarimo:   rarimo,
+		tls:      tls,
+		auth:     auth,
+		revoter:  revoter,
+		health:   health,
+		deposits: deposits,
+		admin:    admin,
 	}
 }
 
 func (s *SaverService) Run() error {
-	grpcServer := grpc.NewServer()
+	var opts []grpc.ServerOption
+	if s.tls != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tls)))
+	}
+
+	if s.auth != nil {
+		opts = append(opts,
+			grpc.UnaryInterceptor(s.auth.UnaryInterceptor(methodRoles)),
+			grpc.StreamInterceptor(s.auth.StreamInterceptor(methodRoles)),
+		)
+	}
+
+	grpcServer := grpc.NewServer(opts...)
 	lib.RegisterSaverServer(grpcServer, s)
+	api.RegisterDepositsServer(grpcServer, s.deposits)
+	api.RegisterAdminServer(grpcServer, s.admin)
+	healthpb.RegisterHealthServer(grpcServer, s.health.Server())
+
+	if err := registerReflection(grpcServer); err != nil {
+		return errors.Wrap(err, "error registering reflection")
+	}
+
 	return grpcServer.Serve(s.listener)
 }
 
 // gRPC service implementation
 var _ lib.SaverServer = &SaverService{}
 
+// Revote verifies the operation and broadcasts the vote. Result is the broadcasted vote.
+// Errors have the ErrorInfo details with the failure reason, see revoteError.
 func (s *SaverService) Revote(ctx context.Context, req *lib.RevoteRequest) (*lib.RevoteResponse, error) {
-	op, err := rarimotypes.NewQueryClient(s.rarimo).Operation(ctx, &rarimotypes.QueryGetOperationRequest{Index: req.Operation})
-	if err != nil {
-		s.log.WithError(err).Error("error fetching op")
-		return nil, status.Error(codes.Internal, "Internal error")
+	if req.Operation == "" {
+		return nil, status.Error(codes.InvalidArgument, "operation is required")
 	}
 
-	if err := s.voter.Process(ctx, op.Operation); err != nil {
-		s.log.WithError(err).Error("error processing op")
-		return nil, status.Error(codes.Internal, "Internal error")
+	result := s.revoter.RevoteOperation(ctx, req.Operation)
+	if result.Err != nil {
+		logRevoteError(s.log, result)
+		return nil, revoteError(result)
 	}
 
-	return &lib.RevoteResponse{}, nil
+	return &lib.RevoteResponse{Result: result.Vote.String()}, nil
 }
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	v1alphagrpc "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// gogoFiles are the gogo generated proto files served by the gRPC server.
+// Their descriptors are registered in the gogo registry only, so they should be loaded for reflection manually.
+var gogoFiles = []string{
+	"proto/service.proto",
+}
+
+// registerReflection registers the reflection service resolving both gogo and golang/protobuf generated descriptors.
+func registerReflection(server *grpc.Server) error {
+	files := new(protoregistry.Files)
+
+	for _, name := range gogoFiles {
+		file, err := gogoFile(name)
+		if err != nil {
+			return errors.Wrap(err, "error loading gogo proto file", logan.F{"file": name})
+		}
+
+		if err := files.RegisterFile(file); err != nil {
+			return errors.Wrap(err, "error registering gogo proto file", logan.F{"file": name})
+		}
+	}
+
+	v1alphagrpc.RegisterServerReflectionServer(server, reflection.NewServer(reflection.ServerOptions{
+		Services:           server,
+		DescriptorResolver: resolver{files},
+	}))
+
+	return nil
+}
+
+func gogoFile(name string) (protoreflect.FileDescriptor, error) {
+	compressed := gogoproto.FileDescriptor(name)
+	if compressed == nil {
+		return nil, errors.New("proto file is not registered")
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening gzip reader")
+	}
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decompressing descriptor")
+	}
+
+	var descriptor descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(raw, &descriptor); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling descriptor")
+	}
+
+	return protodesc.NewFile(&descriptor, protoregistry.GlobalFiles)
+}
+
+// resolver looks up the gogo proto files first and falls back to the global registry.
+type resolver struct {
+	gogo *protoregistry.Files
+}
+
+func (r resolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if file, err := r.gogo.FindFileByPath(path); err == nil {
+		return file, nil
+	}
+
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r resolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if descriptor, err := r.gogo.FindDescriptorByName(name); err == nil {
+		return descriptor, nil
+	}
+
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
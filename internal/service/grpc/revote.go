@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service/pause"
+	"github.com/rarimo/sol-saver-svc/internal/service/voter"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details attached to the revote errors.
+const errorDomain = "sol-saver-svc"
+
+// Reasons of the ErrorInfo details attached to the revote errors.
+const (
+	ReasonOperationNotFound     = "OPERATION_NOT_FOUND"
+	ReasonOperationProcessed    = "OPERATION_PROCESSED"
+	ReasonOperationVoted        = "OPERATION_VOTED"
+	ReasonUnsupportedOperation  = "UNSUPPORTED_OPERATION"
+	ReasonUnsupportedNetwork    = "UNSUPPORTED_NETWORK"
+	ReasonWrongOperationContent = "WRONG_OPERATION_CONTENT"
+	ReasonMalformedOperation    = "MALFORMED_OPERATION"
+	ReasonPaused                = "PAUSED"
+	ReasonCancelled             = "CANCELLED"
+	ReasonTimeout               = "TIMEOUT"
+	ReasonUnavailable           = "UNAVAILABLE"
+	ReasonInternal              = "INTERNAL"
+)
+
+type revoteFailure struct {
+	code   codes.Code
+	reason string
+	// message replaces the error text that can contain internal details, error text is used if empty
+	message string
+}
+
+var revoteFailures = map[error]revoteFailure{
+	voter.ErrOperationNotFound:         {code: codes.NotFound, reason: ReasonOperationNotFound},
+	voter.ErrOperationProcessed:        {code: codes.FailedPrecondition, reason: ReasonOperationProcessed},
+	voter.ErrOperationVoted:            {code: codes.FailedPrecondition, reason: ReasonOperationVoted},
+	voter.ErrMalformedOperation:        {code: codes.FailedPrecondition, reason: ReasonMalformedOperation, message: "operation details can not be decoded"},
+	verifiers.ErrInvalidOperationType:  {code: codes.InvalidArgument, reason: ReasonUnsupportedOperation},
+	verifiers.ErrUnsupportedNetwork:    {code: codes.InvalidArgument, reason: ReasonUnsupportedNetwork},
+	verifiers.ErrWrongOperationContent: {code: codes.FailedPrecondition, reason: ReasonWrongOperationContent},
+	pause.ErrPaused:                    {code: codes.FailedPrecondition, reason: ReasonPaused, message: "voter is paused"},
+	context.Canceled:                   {code: codes.Canceled, reason: ReasonCancelled, message: "revote cancelled"},
+	context.DeadlineExceeded:           {code: codes.DeadlineExceeded, reason: ReasonTimeout, message: "verification timed out"},
+	voter.ErrUnavailable:               {code: codes.Unavailable, reason: ReasonUnavailable, message: "core, Solana RPC or broadcaster is unavailable"},
+}
+
+// unexpectedFailure is the failure that is not caused by the operation or its dependencies, e.g. the panic.
+var unexpectedFailure = revoteFailure{code: codes.Internal, reason: ReasonInternal, message: "internal error"}
+
+func toRevoteFailure(err error) revoteFailure {
+	if failure, ok := revoteFailures[errors.Cause(err)]; ok {
+		return failure
+	}
+
+	return unexpectedFailure
+}
+
+// revoteMessage returns the error message of the failed revote safe to be returned to the caller.
+func revoteMessage(result voter.RevoteResult) string {
+	if failure := toRevoteFailure(result.Err); failure.message != "" {
+		return failure.message
+	}
+
+	return result.Err.Error()
+}
+
+// logRevoteError logs the dependency and unexpected failures hidden from the caller behind the sanitized message.
+func logRevoteError(log *logan.Entry, result voter.RevoteResult) {
+	if result.Err == nil {
+		return
+	}
+
+	if code := toRevoteFailure(result.Err).code; code == codes.Unavailable || code == codes.Internal {
+		log.WithError(result.Err).WithField("index", result.Index).Error("error revoting operation")
+	}
+}
+
+// revoteError converts the failed revote result to the gRPC status with the ErrorInfo details.
+// Core, Solana RPC or broadcaster outages are reported as UNAVAILABLE, so the call can be retried.
+func revoteError(result voter.RevoteResult) error {
+	failure := toRevoteFailure(result.Err)
+
+	info := &errdetails.ErrorInfo{
+		Reason: failure.reason,
+		Domain: errorDomain,
+		Metadata: map[string]string{
+			"operation": result.Index,
+		},
+	}
+
+	// The NO vote is broadcasted for the operations not matching the deposit
+	if failure.reason == ReasonWrongOperationContent {
+		info.Metadata["vote"] = result.Vote.String()
+	}
+
+	st := status.New(failure.code, revoteMessage(result))
+	if detailed, err := st.WithDetails(info); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
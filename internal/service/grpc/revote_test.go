@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	goerr "errors"
+	"testing"
+
+	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service/pause"
+	"github.com/rarimo/sol-saver-svc/internal/service/voter"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevoteError(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		reason  string
+		message string
+	}{
+		{name: "not found", err: voter.ErrOperationNotFound, code: codes.NotFound, reason: ReasonOperationNotFound, message: "operation not found"},
+		{name: "voted", err: voter.ErrOperationVoted, code: codes.FailedPrecondition, reason: ReasonOperationVoted, message: "operation is already voted"},
+		{name: "wrong content keeps reason", err: errors.Wrap(verifiers.ErrWrongOperationContent, "invalid event id"), code: codes.FailedPrecondition, reason: ReasonWrongOperationContent, message: "invalid event id: wrong operation content"},
+		{name: "malformed operation", err: errors.Wrap(voter.ErrMalformedOperation, "proto: illegal wireType"), code: codes.FailedPrecondition, reason: ReasonMalformedOperation, message: "operation details can not be decoded"},
+		{name: "unsupported network", err: errors.Wrap(verifiers.ErrUnsupportedNetwork, "verification failed"), code: codes.InvalidArgument, reason: ReasonUnsupportedNetwork},
+		{name: "paused", err: errors.Wrap(pause.ErrPaused, "error broadcasting vote"), code: codes.FailedPrecondition, reason: ReasonPaused, message: "voter is paused"},
+		{name: "timeout", err: errors.Wrap(context.DeadlineExceeded, "verification failed"), code: codes.DeadlineExceeded, reason: ReasonTimeout, message: "verification timed out"},
+		{name: "unavailable hides details", err: errors.Wrap(voter.ErrUnavailable, "error fetching operation: dial tcp 10.0.0.1:9090"), code: codes.Unavailable, reason: ReasonUnavailable, message: "core, Solana RPC or broadcaster is unavailable"},
+		{name: "unexpected hides details", err: goerr.New("operation revote panicked"), code: codes.Internal, reason: ReasonInternal, message: "internal error"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := revoteError(voter.RevoteResult{Index: "0x01", Vote: rarimotypes.VoteType_NO, Err: c.err})
+
+			st := status.Convert(err)
+			if st.Code() != c.code {
+				t.Fatalf("expected code %s, got %s", c.code, st.Code())
+			}
+
+			if c.message != "" && st.Message() != c.message {
+				t.Fatalf("expected message %q, got %q", c.message, st.Message())
+			}
+
+			if len(st.Details()) != 1 {
+				t.Fatalf("expected single detail, got %v", st.Details())
+			}
+
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			if !ok {
+				t.Fatalf("expected ErrorInfo, got %T", st.Details()[0])
+			}
+
+			if info.Reason != c.reason || info.Domain != errorDomain || info.Metadata["operation"] != "0x01" {
+				t.Fatalf("unexpected error info %v", info)
+			}
+
+			if _, ok := info.Metadata["vote"]; ok != (c.reason == ReasonWrongOperationContent) {
+				t.Fatalf("unexpected vote metadata %v", info.Metadata)
+			}
+		})
+	}
+}
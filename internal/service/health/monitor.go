@@ -0,0 +1,145 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/olegfomenko/solana-go/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Components reported as separate services of the gRPC health server.
+// The overall service status (empty service name) is SERVING only if all tracked components are healthy.
+const (
+	ComponentSolanaRPC = "solana_rpc"
+	ComponentSolanaWS  = "solana_ws"
+	ComponentCore      = "core"
+)
+
+// Monitor periodically probes the service dependencies and reports their status to the gRPC health server.
+// Solana websocket is tracked only after the listener has reported its state.
+type Monitor struct {
+	log     *logan.Entry
+	server  *grpchealth.Server
+	solana  *rpc.Client
+	core    *grpc.ClientConn
+	period  time.Duration
+	timeout time.Duration
+
+	mu     sync.Mutex
+	status map[string]bool
+}
+
+func NewMonitor(log *logan.Entry, solana *rpc.Client, core *grpc.ClientConn, period, timeout time.Duration) *Monitor {
+	m := &Monitor{
+		log:     log,
+		server:  grpchealth.NewServer(),
+		solana:  solana,
+		core:    core,
+		period:  period,
+		timeout: timeout,
+		status: map[string]bool{
+			ComponentSolanaRPC: false,
+			ComponentCore:      false,
+		},
+	}
+
+	m.report()
+	return m
+}
+
+// Server returns the gRPC health server to be registered on the gRPC server.
+func (m *Monitor) Server() *grpchealth.Server {
+	return m.server
+}
+
+// SetWebsocket reports the state of the Solana websocket subscription.
+func (m *Monitor) SetWebsocket(subscribed bool) {
+	m.set(ComponentSolanaWS, subscribed)
+}
+
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.period)
+	defer ticker.Stop()
+
+	for {
+		m.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			m.server.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	if err := m.checkSolana(ctx); err != nil {
+		m.log.WithError(err).Warn("solana rpc is unhealthy")
+		m.set(ComponentSolanaRPC, false)
+	} else {
+		m.set(ComponentSolanaRPC, true)
+	}
+
+	m.set(ComponentCore, m.checkCore())
+}
+
+func (m *Monitor) checkSolana(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	health, err := m.solana.GetHealth(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error getting solana health")
+	}
+
+	if health != rpc.HealthOk {
+		return errors.From(errors.New("solana node is not healthy"), logan.F{"health": health})
+	}
+
+	return nil
+}
+
+func (m *Monitor) checkCore() bool {
+	switch m.core.GetState() {
+	case connectivity.Ready:
+		return true
+	case connectivity.Idle:
+		// idle connection is healthy but should be connected to be checked next time
+		m.core.Connect()
+		return true
+	}
+
+	return false
+}
+
+func (m *Monitor) set(component string, healthy bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status[component] = healthy
+	m.report()
+}
+
+func (m *Monitor) report() {
+	overall := healthpb.HealthCheckResponse_SERVING
+
+	for component, healthy := range m.status {
+		status := healthpb.HealthCheckResponse_SERVING
+		if !healthy {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			overall = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+
+		m.server.SetServingStatus(component, status)
+	}
+
+	m.server.SetServingStatus("", overall)
+}
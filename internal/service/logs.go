@@ -0,0 +1,72 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/olegfomenko/solana-go"
+)
+
+const (
+	logProgramPrefix  = "Program"
+	logMessagePrefix  = "Program log: "
+	logTruncated      = "Log truncated"
+	logInvokeKeyword  = "invoke"
+	logSuccessKeyword = "success"
+	logFailedKeyword  = "failed:"
+)
+
+// Invocation is the execution of the top-level transaction instruction parsed from the transaction logs.
+type Invocation struct {
+	Program solana.PublicKey
+	Success bool
+	// Messages logged by the invoked program itself (without the "Program log: " prefix).
+	Logs []string
+}
+
+// ParseInvocations parses transaction log messages into the list of top-level instruction invocations.
+// The list index corresponds to the instruction index in the transaction message.
+// If the logs were truncated, only invocations logged before truncation are returned and truncated flag is set.
+func ParseInvocations(logs []string) (invocations []Invocation, truncated bool) {
+	var depth int
+
+	for _, line := range logs {
+		if line == logTruncated {
+			return invocations, true
+		}
+
+		if strings.HasPrefix(line, logMessagePrefix) {
+			if depth == 1 {
+				current := &invocations[len(invocations)-1]
+				current.Logs = append(current.Logs, strings.TrimPrefix(line, logMessagePrefix))
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != logProgramPrefix {
+			continue
+		}
+
+		switch fields[2] {
+		case logInvokeKeyword:
+			if depth == 0 {
+				// zero program key in case of parsing error will not match any program
+				program, _ := solana.PublicKeyFromBase58(fields[1])
+				invocations = append(invocations, Invocation{Program: program})
+			}
+
+			depth++
+		case logSuccessKeyword, logFailedKeyword:
+			if depth == 0 {
+				continue
+			}
+
+			depth--
+			if depth == 0 {
+				invocations[len(invocations)-1].Success = fields[2] == logSuccessKeyword
+			}
+		}
+	}
+
+	return invocations, false
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/olegfomenko/solana-go"
+)
+
+func TestParseInvocations(t *testing.T) {
+	bridge := solana.NewWallet().PublicKey()
+	token := solana.TokenProgramID
+	system := solana.SystemProgramID
+
+	invoke := func(program solana.PublicKey, depth string) string {
+		return "Program " + program.String() + " invoke [" + depth + "]"
+	}
+	success := func(program solana.PublicKey) string {
+		return "Program " + program.String() + " success"
+	}
+	failed := func(program solana.PublicKey) string {
+		return "Program " + program.String() + " failed: custom program error: 0x1"
+	}
+
+	cases := []struct {
+		name          string
+		logs          []string
+		want          []Invocation
+		wantTruncated bool
+	}{
+		{name: "no logs"},
+		{
+			name: "single invocation",
+			logs: []string{
+				invoke(bridge, "1"),
+				"Program log: Instruction: DepositNative",
+				"Program " + bridge.String() + " consumed 1000 of 200000 compute units",
+				success(bridge),
+			},
+			want: []Invocation{{Program: bridge, Success: true, Logs: []string{"Instruction: DepositNative"}}},
+		},
+		{
+			name: "nested invocation logs are not attributed to the top-level program",
+			logs: []string{
+				invoke(bridge, "1"),
+				"Program log: Instruction: DepositFT",
+				invoke(token, "2"),
+				"Program log: Instruction: Transfer",
+				success(token),
+				"Program log: deposited",
+				success(bridge),
+			},
+			want: []Invocation{{Program: bridge, Success: true, Logs: []string{"Instruction: DepositFT", "deposited"}}},
+		},
+		{
+			name: "failed nested invocation does not fail the top-level one",
+			logs: []string{
+				invoke(bridge, "1"),
+				invoke(token, "2"),
+				failed(token),
+				success(bridge),
+			},
+			want: []Invocation{{Program: bridge, Success: true}},
+		},
+		{
+			name: "several top-level invocations",
+			logs: []string{
+				invoke(system, "1"),
+				success(system),
+				invoke(bridge, "1"),
+				"Program log: first",
+				failed(bridge),
+			},
+			want: []Invocation{{Program: system, Success: true}, {Program: bridge, Logs: []string{"first"}}},
+		},
+		{
+			name: "truncated logs",
+			logs: []string{
+				invoke(system, "1"),
+				success(system),
+				invoke(bridge, "1"),
+				"Program log: first",
+				"Log truncated",
+				success(bridge),
+			},
+			want:          []Invocation{{Program: system, Success: true}, {Program: bridge, Logs: []string{"first"}}},
+			wantTruncated: true,
+		},
+		{
+			name: "truncated inside nested invocation",
+			logs: []string{
+				invoke(bridge, "1"),
+				invoke(token, "2"),
+				"Log truncated",
+			},
+			want:          []Invocation{{Program: bridge}},
+			wantTruncated: true,
+		},
+		{
+			name: "unparsable program key",
+			logs: []string{
+				"Program not-a-key invoke [1]",
+				"Program not-a-key success",
+			},
+			want: []Invocation{{Program: solana.PublicKey{}, Success: true}},
+		},
+		{
+			name: "unbalanced and unrelated lines are ignored",
+			logs: []string{
+				success(bridge),
+				"Program log: orphan message",
+				"Program",
+				"Program " + bridge.String(),
+				"Program data: AAAA",
+				invoke(bridge, "1"),
+				success(bridge),
+			},
+			want: []Invocation{{Program: bridge, Success: true}},
+		},
+		{
+			name: "unfinished invocation",
+			logs: []string{
+				invoke(bridge, "1"),
+				"Program log: started",
+			},
+			want: []Invocation{{Program: bridge, Logs: []string{"started"}}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			invocations, truncated := ParseInvocations(c.logs)
+			if truncated != c.wantTruncated {
+				t.Fatalf("expected truncated %v, got %v", c.wantTruncated, truncated)
+			}
+
+			if !reflect.DeepEqual(invocations, c.want) {
+				t.Fatalf("expected %+v, got %+v", c.want, invocations)
+			}
+		})
+	}
+}
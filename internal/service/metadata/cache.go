@@ -0,0 +1,100 @@
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+const (
+	urisDir    = "uris"
+	contentDir = "content"
+)
+
+// cache stores the content of immutable URIs on disk addressed by its sha256 hash.
+// URI entries contain the hash of the content that was fetched by URI.
+type cache struct {
+	dir string
+}
+
+func newCache(dir string) *cache {
+	if dir == "" {
+		return &cache{}
+	}
+
+	for _, sub := range []string{urisDir, contentDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			panic(err)
+		}
+	}
+
+	return &cache{dir: dir}
+}
+
+func (c *cache) get(uri string) ([]byte, bool) {
+	if c.dir == "" {
+		return nil, false
+	}
+
+	hash, err := os.ReadFile(c.uriPath(uri))
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, contentDir, string(hash)))
+	if err != nil {
+		return nil, false
+	}
+
+	// ignoring corrupted entries
+	if hashOf(data) != string(hash) {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// put stores the content ignoring errors: cache misses only lead to fetching the content again
+func (c *cache) put(uri string, data []byte) {
+	if c.dir == "" {
+		return
+	}
+
+	hash := hashOf(data)
+	if writeFile(filepath.Join(c.dir, contentDir, hash), data) != nil {
+		return
+	}
+
+	_ = writeFile(c.uriPath(uri), []byte(hash))
+}
+
+func (c *cache) uriPath(uri string) string {
+	return filepath.Join(c.dir, urisDir, hashOf([]byte(uri)))
+}
+
+func hashOf(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// writeFile writes the file atomically to avoid reading partially written content
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
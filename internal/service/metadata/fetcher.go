@@ -0,0 +1,175 @@
+package metadata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type Config struct {
+	// Gateways used to fetch ipfs:// and ar:// URIs
+	IPFSGateway    string `fig:"ipfs_gateway"`
+	ArweaveGateway string `fig:"arweave_gateway"`
+	// Max duration of the single request
+	Timeout time.Duration `fig:"timeout"`
+	// Max size in bytes of the metadata JSON and the image
+	MaxMetadataSize int64 `fig:"max_metadata_size"`
+	MaxImageSize    int64 `fig:"max_image_size"`
+	// Allowed content type prefixes of the metadata JSON and the image
+	MetadataContentTypes []string `fig:"metadata_content_types"`
+	ImageContentTypes    []string `fig:"image_content_types"`
+	// Directory to cache the content of immutable (IPFS and Arweave) URIs. Cache is disabled if empty.
+	CacheDir string `fig:"cache_dir"`
+}
+
+// Fetcher fetches the off-chain NFT metadata and its image.
+type Fetcher struct {
+	cfg    Config
+	client *http.Client
+	cache  *cache
+}
+
+func NewFetcher(cfg Config) *Fetcher {
+	return &Fetcher{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cache:  newCache(cfg.CacheDir),
+	}
+}
+
+type offChainMetadata struct {
+	Image string `json:"image"`
+}
+
+// GetImage fetches the off-chain metadata JSON by the provided URI and returns the image URI
+// and base64 encoded sha256 hash of the image content (the same as verifiers.GetImage does).
+// Image URI is returned as it is set in the metadata, gateways are used for fetching only.
+func (f *Fetcher) GetImage(ctx context.Context, uri string) (string, string, error) {
+	data, err := f.get(ctx, uri, f.cfg.MaxMetadataSize, f.cfg.MetadataContentTypes)
+	if err != nil {
+		return "", "", errors.Wrap(err, "error fetching metadata", logan.F{"uri": uri})
+	}
+
+	meta := new(offChainMetadata)
+	if err := json.Unmarshal(data, meta); err != nil {
+		return "", "", errors.Wrap(err, "error parsing metadata", logan.F{"uri": uri})
+	}
+
+	if meta.Image == "" {
+		return "", "", errors.From(errors.New("metadata has no image"), logan.F{"uri": uri})
+	}
+
+	image, err := f.get(ctx, meta.Image, f.cfg.MaxImageSize, f.cfg.ImageContentTypes)
+	if err != nil {
+		return "", "", errors.Wrap(err, "error fetching image", logan.F{"uri": meta.Image})
+	}
+
+	hash := sha256.Sum256(image)
+	return meta.Image, base64.StdEncoding.EncodeToString(hash[:]), nil
+}
+
+// get returns the content by URI from the cache (for immutable URIs) or fetches it via gateway.
+func (f *Fetcher) get(ctx context.Context, uri string, limit int64, contentTypes []string) ([]byte, error) {
+	url, immutable, err := f.resolve(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if immutable {
+		if data, ok := f.cache.get(uri); ok {
+			return data, nil
+		}
+	}
+
+	data, err := f.fetch(ctx, url, limit, contentTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if immutable {
+		f.cache.put(uri, data)
+	}
+
+	return data, nil
+}
+
+// resolve returns the HTTP URL of the URI and whether its content is immutable.
+func (f *Fetcher) resolve(uri string) (string, bool, error) {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return strings.TrimSuffix(f.cfg.IPFSGateway, "/") + "/" + path, true, nil
+	case strings.HasPrefix(uri, "ar://"):
+		return strings.TrimSuffix(f.cfg.ArweaveGateway, "/") + "/" + strings.TrimPrefix(uri, "ar://"), true, nil
+	case strings.HasPrefix(uri, "https://"), strings.HasPrefix(uri, "http://"):
+		return uri, false, nil
+	}
+
+	return "", false, errors.From(errors.New("unsupported URI scheme"), logan.F{"uri": uri})
+}
+
+func (f *Fetcher) fetch(ctx context.Context, url string, limit int64, contentTypes []string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating request")
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error sending request")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.From(errors.New("unexpected response status"), logan.F{"status": resp.StatusCode})
+	}
+
+	if resp.ContentLength > limit {
+		return nil, errors.From(errors.New("content is too large"), logan.F{"size": resp.ContentLength, "limit": limit})
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response")
+	}
+
+	if int64(len(data)) > limit {
+		return nil, errors.From(errors.New("content is too large"), logan.F{"limit": limit})
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	if !allowedContentType(contentType, contentTypes) {
+		return nil, errors.From(errors.New("content type is not allowed"), logan.F{"content_type": contentType})
+	}
+
+	return data, nil
+}
+
+func allowedContentType(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, prefix := range allowed {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	QuorumKindTransaction = "transaction"
+	QuorumKindAccount     = "account"
+)
+
+var (
+	QuorumDisagreementMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "solana_quorum_disagreements_total",
+	}, []string{"kind"})
+
+	QuorumNotReachedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "solana_quorum_not_reached_total",
+	}, []string{"kind"})
+)
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// mint authority option (4 + 32) + supply (8)
+const mintDecimalsOffset = 44
+
+// ParseMintDecimals returns the decimals of SPL Token or Token-2022 mint account data.
+func ParseMintDecimals(data []byte) (uint8, error) {
+	if len(data) < mintSize {
+		return 0, errors.From(errors.New("invalid mint data length"), logan.F{"length": len(data)})
+	}
+
+	return data[mintDecimalsOffset], nil
+}
@@ -0,0 +1,36 @@
+package pause
+
+import (
+	"context"
+	goerr "errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rarimo/saver-grpc-lib/broadcaster"
+)
+
+var ErrPaused = goerr.New("broadcasting is paused")
+
+// Broadcaster refuses to broadcast the messages while the component is paused.
+type Broadcaster struct {
+	broadcaster.Broadcaster
+	pause     *Switch
+	component Component
+}
+
+func NewBroadcaster(b broadcaster.Broadcaster, pause *Switch, component Component) *Broadcaster {
+	return &Broadcaster{
+		Broadcaster: b,
+		pause:       pause,
+		component:   component,
+	}
+}
+
+var _ broadcaster.Broadcaster = &Broadcaster{}
+
+func (b *Broadcaster) BroadcastTx(ctx context.Context, msgs ...sdk.Msg) error {
+	if b.pause.Paused(b.component) {
+		return ErrPaused
+	}
+
+	return b.Broadcaster.BroadcastTx(ctx, msgs...)
+}
@@ -0,0 +1,29 @@
+package pause
+
+import (
+	"os"
+	"syscall"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// lockFile takes the exclusive lock shared by all processes using the file (service modes and CLI),
+// so the read-modify-write of the file is never interleaved. The lock is held on the separate file
+// because the file itself is replaced on every write.
+func lockFile(path string) (unlock func(), err error) {
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening lock file", logan.F{"path": path})
+	}
+
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		lock.Close()
+		return nil, errors.Wrap(err, "error locking file", logan.F{"path": path})
+	}
+
+	return func() {
+		_ = syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+		lock.Close()
+	}, nil
+}
@@ -0,0 +1,163 @@
+package pause
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// PendingDeposit is the deposit observed while the saver was paused.
+type PendingDeposit struct {
+	Tx      string `json:"tx"`
+	EventId string `json:"event_id"`
+	Slot    uint64 `json:"slot"`
+	// Instruction is the bridge program deposit instruction code
+	Instruction uint8     `json:"instruction"`
+	Msg         []byte    `json:"msg"` // proto encoded oracletypes.MsgCreateTransferOp
+	QueuedAt    time.Time `json:"queued_at"`
+}
+
+func (d PendingDeposit) Message() (*oracletypes.MsgCreateTransferOp, error) {
+	msg := new(oracletypes.MsgCreateTransferOp)
+	if err := msg.Unmarshal(d.Msg); err != nil {
+		return nil, errors.Wrap(err, "error decoding pending deposit message", logan.F{"tx": d.Tx, "event_id": d.EventId})
+	}
+
+	return msg, nil
+}
+
+// Pending is the queue of the deposits waiting for the saver to be resumed.
+// Queue is persisted in the file shared by all processes: it is reloaded and rewritten atomically
+// under the file lock on every change, so deposits queued by other processes are never lost.
+type Pending struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewPending(path string) (*Pending, error) {
+	p := &Pending{path: path}
+
+	// Checking the queue can be read
+	if _, err := p.List(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Push queues the deposit message of the instruction executed in the slot, returns false if it is already queued.
+func (p *Pending) Push(slot uint64, instruction uint8, msg *oracletypes.MsgCreateTransferOp) (bool, error) {
+	data, err := msg.Marshal()
+	if err != nil {
+		return false, errors.Wrap(err, "error encoding deposit message")
+	}
+
+	queued := false
+
+	err = p.update(func(deposits []PendingDeposit) []PendingDeposit {
+		for _, d := range deposits {
+			if d.Tx == msg.Tx && d.EventId == msg.EventId {
+				return nil
+			}
+		}
+
+		queued = true
+		return append(deposits, PendingDeposit{
+			Tx:          msg.Tx,
+			EventId:     msg.EventId,
+			Slot:        slot,
+			Instruction: instruction,
+			Msg:         data,
+			QueuedAt:    time.Now().UTC(),
+		})
+	})
+
+	return queued, err
+}
+
+// List returns the queued deposits in the order they were observed.
+func (p *Pending) List() ([]PendingDeposit, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	unlock, err := lockFile(p.path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return p.load()
+}
+
+// Remove removes the deposit from the queue after it was broadcasted.
+func (p *Pending) Remove(tx, eventId string) error {
+	return p.update(func(deposits []PendingDeposit) []PendingDeposit {
+		result := make([]PendingDeposit, 0, len(deposits))
+		for _, d := range deposits {
+			if d.Tx != tx || d.EventId != eventId {
+				result = append(result, d)
+			}
+		}
+
+		if len(result) == len(deposits) {
+			return nil
+		}
+
+		return result
+	})
+}
+
+// update reloads the queue and saves the result of the change under the file lock. Nothing is saved if change returns nil.
+func (p *Pending) update(change func([]PendingDeposit) []PendingDeposit) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	unlock, err := lockFile(p.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	deposits, err := p.load()
+	if err != nil {
+		return err
+	}
+
+	deposits = change(deposits)
+	if deposits == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(deposits, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error encoding pending deposits")
+	}
+
+	return writeFile(p.path, data)
+}
+
+func (p *Pending) load() ([]PendingDeposit, error) {
+	deposits := []PendingDeposit{}
+
+	data, err := os.ReadFile(p.path)
+	if os.IsNotExist(err) {
+		return deposits, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading pending deposits", logan.F{"path": p.path})
+	}
+
+	if len(bytes.TrimSpace(data)) != 0 {
+		if err := json.Unmarshal(data, &deposits); err != nil {
+			return nil, errors.Wrap(err, "error decoding pending deposits", logan.F{"path": p.path})
+		}
+	}
+
+	return deposits, nil
+}
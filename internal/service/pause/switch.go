@@ -0,0 +1,265 @@
+package pause
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Component is the part of the service that can be paused.
+type Component string
+
+const (
+	// Saver broadcasts MsgCreateTransferOp for the observed deposits
+	Saver Component = "saver"
+	// Voter broadcasts votes for the core operations
+	Voter Component = "voter"
+)
+
+var Components = []Component{Saver, Voter}
+
+func ParseComponent(component string) (Component, error) {
+	for _, c := range Components {
+		if string(c) == component {
+			return c, nil
+		}
+	}
+
+	return "", errors.From(errors.New("unknown component"), logan.F{"component": component})
+}
+
+// State is the persisted pause state.
+type State struct {
+	Saver     bool      `json:"saver"`
+	Voter     bool      `json:"voter"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s State) Paused(c Component) bool {
+	switch c {
+	case Saver:
+		return s.Saver
+	case Voter:
+		return s.Voter
+	default:
+		return false
+	}
+}
+
+func (s *State) set(c Component, paused bool) {
+	switch c {
+	case Saver:
+		s.Saver = paused
+	case Voter:
+		s.Voter = paused
+	}
+}
+
+// Switch keeps the pause state persisted in the file, so it survives the restarts and can be changed
+// by the CLI while the service is running: Run reloads the file periodically.
+// Resume hooks are called in background when the component is resumed.
+type Switch struct {
+	log    *logan.Entry
+	path   string
+	period time.Duration
+
+	mu     sync.RWMutex
+	state  State
+	resume map[Component][]func()
+}
+
+// NewSwitch loads the pause state from the file. Nothing is paused if the file does not exist.
+func NewSwitch(log *logan.Entry, path string, period time.Duration) (*Switch, error) {
+	s := &Switch{
+		log:    log,
+		path:   path,
+		period: period,
+		resume: make(map[Component][]func()),
+	}
+
+	state, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.state = state
+	return s, nil
+}
+
+// Load reads the pause state from the file.
+func Load(path string) (State, error) {
+	var state State
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, errors.Wrap(err, "error reading pause state", logan.F{"path": path})
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return state, nil
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, errors.Wrap(err, "error decoding pause state", logan.F{"path": path})
+	}
+
+	return state, nil
+}
+
+// Save writes the pause state to the file.
+func Save(path string, state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error encoding pause state")
+	}
+
+	return writeFile(path, data)
+}
+
+// Update pauses or resumes the components in the file under the file lock.
+func Update(path string, paused bool, components ...Component) (State, error) {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return State{}, err
+	}
+	defer unlock()
+
+	state, err := Load(path)
+	if err != nil {
+		return state, err
+	}
+
+	for _, c := range components {
+		state.set(c, paused)
+	}
+	state.UpdatedAt = time.Now().UTC()
+
+	return state, Save(path, state)
+}
+
+// writeFile replaces the file atomically, so the state is never partially written.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "error creating temporary file", logan.F{"path": path})
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "error writing temporary file")
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "error syncing temporary file")
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "error closing temporary file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), path), "error replacing file", logan.F{"path": path})
+}
+
+func (s *Switch) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+func (s *Switch) Paused(c Component) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.Paused(c)
+}
+
+// Set pauses or resumes the component and persists the state, changes made by the CLI are kept.
+func (s *Switch) Set(c Component, paused bool) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := Update(s.path, paused, c)
+	if err != nil {
+		return s.state, err
+	}
+
+	s.apply(state)
+	return state, nil
+}
+
+// OnResume registers the hook called when the component is resumed.
+func (s *Switch) OnResume(c Component, hook func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resume[c] = append(s.resume[c], hook)
+}
+
+// Run reloads the state changed in the file by the CLI.
+func (s *Switch) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		state, err := Load(s.path)
+		if err != nil {
+			s.log.WithError(err).Error("failed to reload pause state")
+			continue
+		}
+
+		s.mu.Lock()
+		if state != s.state {
+			s.apply(state)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// apply sets the new state and calls resume hooks of the resumed components. Should be called under the lock.
+func (s *Switch) apply(state State) {
+	prev := s.state
+	s.state = state
+
+	for _, c := range Components {
+		if prev.Paused(c) == state.Paused(c) {
+			continue
+		}
+
+		if state.Paused(c) {
+			s.log.WithField("component", c).Warn("Broadcasting paused")
+			continue
+		}
+
+		s.log.WithField("component", c).Info("Broadcasting resumed")
+
+		for _, hook := range s.resume[c] {
+			go s.runHook(c, hook)
+		}
+	}
+}
+
+func (s *Switch) runHook(c Component, hook func()) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			s.log.WithRecover(rvr).WithField("component", c).Error("resume hook panicked")
+		}
+	}()
+
+	hook()
+}
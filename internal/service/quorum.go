@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	goerr "errors"
+	"sync"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+var ErrQuorumNotReached = goerr.New("solana providers quorum not reached")
+
+// Quorum requests Solana data from several independent RPC providers and returns it
+// only if at least threshold of them have returned the same content.
+// Quorum with the single provider and threshold = 1 behaves as the plain RPC client.
+type Quorum struct {
+	log       *logan.Entry
+	threshold int
+	providers []*rpc.Client
+}
+
+func NewQuorum(log *logan.Entry, threshold int, providers ...*rpc.Client) *Quorum {
+	return &Quorum{
+		log:       log,
+		threshold: threshold,
+		providers: providers,
+	}
+}
+
+type quorumResult struct {
+	key   string
+	value interface{}
+	err   error
+}
+
+// GetTransaction requests Solana transaction entry by signature from every provider.
+// Transaction is returned only if providers agree byte-for-byte on the instruction with the provided index
+// (its program, accounts and data), on the balance changes and logs. Returns <nil> if tx was not successful.
+func (q *Quorum) GetTransaction(ctx context.Context, sig solana.Signature, index int) (*Transaction, error) {
+	value, err := q.request(QuorumKindTransaction, func(cli *rpc.Client) (string, interface{}, error) {
+		tx, err := GetTransaction(ctx, cli, sig)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return depositKey(tx, index), tx, nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transaction", logan.F{"tx": sig.String()})
+	}
+
+	return value.(*Transaction), nil
+}
+
+// Account is the Solana account content compared between providers.
+type Account struct {
+	Owner solana.PublicKey
+	Data  []byte
+}
+
+// GetAccount requests the account owner and data from every provider.
+// Account is returned only if providers agree byte-for-byte on it.
+func (q *Quorum) GetAccount(ctx context.Context, account solana.PublicKey) (*Account, error) {
+	value, err := q.request(QuorumKindAccount, func(cli *rpc.Client) (string, interface{}, error) {
+		info, err := cli.GetAccountInfo(ctx, account)
+		if err != nil {
+			return "", nil, err
+		}
+
+		res := &Account{
+			Owner: info.Value.Owner,
+			Data:  info.Value.Data.GetBinary(),
+		}
+
+		return string(append(res.Owner.Bytes(), res.Data...)), res, nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get account info", logan.F{"account": account.String()})
+	}
+
+	return value.(*Account), nil
+}
+
+// GetAccountData requests the account data from every provider.
+// Data is returned only if providers agree byte-for-byte on it.
+func (q *Quorum) GetAccountData(ctx context.Context, account solana.PublicKey) ([]byte, error) {
+	info, err := q.GetAccount(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.Data, nil
+}
+
+func (q *Quorum) request(kind string, get func(cli *rpc.Client) (string, interface{}, error)) (interface{}, error) {
+	results := make([]quorumResult, len(q.providers))
+
+	var wg sync.WaitGroup
+	for i, cli := range q.providers {
+		wg.Add(1)
+		go func(i int, cli *rpc.Client) {
+			defer wg.Done()
+			results[i].key, results[i].value, results[i].err = get(cli)
+		}(i, cli)
+	}
+	wg.Wait()
+
+	votes := make(map[string]int)
+	var lastErr error
+
+	for i, res := range results {
+		if res.err != nil {
+			q.log.WithError(res.err).WithField("provider", i).Debug("solana provider request failed")
+			lastErr = res.err
+			continue
+		}
+
+		votes[res.key]++
+	}
+
+	if len(votes) > 1 {
+		q.log.WithFields(logan.F{"kind": kind, "variants": len(votes)}).Warn("solana providers disagree")
+		QuorumDisagreementMetric.WithLabelValues(kind).Inc()
+	}
+
+	for _, res := range results {
+		if res.err == nil && votes[res.key] >= q.threshold {
+			return res.value, nil
+		}
+	}
+
+	QuorumNotReachedMetric.WithLabelValues(kind).Inc()
+
+	if len(votes) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return nil, ErrQuorumNotReached
+}
+
+// depositKey encodes the instruction with the provided index, the transaction balance changes and logs
+// to compare them between providers.
+func depositKey(tx *Transaction, index int) string {
+	// unsuccessful transaction
+	if tx == nil {
+		return ""
+	}
+
+	if index < 0 || index >= len(tx.Message.Instructions) {
+		return "\x00"
+	}
+
+	instruction := tx.Message.Instructions[index]
+	keys := tx.Message.AccountKeys
+
+	key := []byte{1}
+	for _, i := range append([]uint16{instruction.ProgramIDIndex}, instruction.Accounts...) {
+		if int(i) >= len(keys) {
+			return "\x00"
+		}
+
+		key = append(key, keys[i].Bytes()...)
+	}
+
+	key = append(key, instruction.Data...)
+
+	if tx.Meta != nil {
+		meta, err := json.Marshal([]interface{}{
+			tx.Meta.PreBalances,
+			tx.Meta.PostBalances,
+			tx.Meta.PreTokenBalances,
+			tx.Meta.PostTokenBalances,
+			tx.Meta.LogMessages,
+		})
+		if err != nil {
+			return "\x00"
+		}
+
+		key = append(key, meta...)
+	}
+
+	return string(key)
+}
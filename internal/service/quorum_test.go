@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/olegfomenko/solana-go/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	logerrors "gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type providerResponse struct {
+	key string
+	err error
+}
+
+func TestQuorumRequest(t *testing.T) {
+	errProvider := errors.New("provider is down")
+
+	cases := []struct {
+		name      string
+		threshold int
+		responses []providerResponse
+		want      string
+		wantErr   error
+	}{
+		{name: "single provider", threshold: 1, responses: []providerResponse{{key: "a"}}, want: "a"},
+		{name: "all agree", threshold: 3, responses: []providerResponse{{key: "a"}, {key: "a"}, {key: "a"}}, want: "a"},
+		{name: "majority agrees", threshold: 2, responses: []providerResponse{{key: "a"}, {key: "b"}, {key: "a"}}, want: "a"},
+		{name: "majority agrees with provider error", threshold: 2, responses: []providerResponse{{key: "a"}, {err: errProvider}, {key: "a"}}, want: "a"},
+		{name: "disagreement below threshold", threshold: 2, responses: []providerResponse{{key: "a"}, {key: "b"}, {key: "c"}}, wantErr: ErrQuorumNotReached},
+		{name: "threshold not met because of provider errors", threshold: 2, responses: []providerResponse{{key: "a"}, {err: errProvider}, {err: errProvider}}, wantErr: ErrQuorumNotReached},
+		{name: "all providers failed", threshold: 1, responses: []providerResponse{{err: errProvider}, {err: errProvider}}, wantErr: errProvider},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			providers := make([]*rpc.Client, len(c.responses))
+			responses := make(map[*rpc.Client]providerResponse, len(c.responses))
+			for i, resp := range c.responses {
+				providers[i] = rpc.New("http://localhost")
+				responses[providers[i]] = resp
+			}
+
+			q := NewQuorum(logan.New().WithField("test", t.Name()), c.threshold, providers...)
+
+			value, err := q.request(QuorumKindAccount, func(cli *rpc.Client) (string, interface{}, error) {
+				resp := responses[cli]
+				return resp.key, resp.key, resp.err
+			})
+
+			if c.wantErr != nil {
+				if logerrors.Cause(err) != c.wantErr {
+					t.Fatalf("expected %v, got %v", c.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if value.(string) != c.want {
+				t.Fatalf("expected %q, got %q", c.want, value)
+			}
+		})
+	}
+}
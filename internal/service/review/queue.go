@@ -0,0 +1,131 @@
+package review
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Entry is the deposit that can not be bridged and should be reviewed for refund.
+type Entry struct {
+	Tx        string    `json:"tx"`
+	EventId   string    `json:"event_id"`
+	Reason    string    `json:"reason"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Queue is the append-only review queue persisted as JSON lines file.
+// Entries are unique by transaction and event id, so reprocessed deposits are queued once.
+// Queue without file path is kept in memory only.
+type Queue struct {
+	mu      sync.RWMutex
+	path    string
+	entries []Entry
+	known   map[string]struct{}
+}
+
+func NewQueue(path string) (*Queue, error) {
+	q := &Queue{
+		path:  path,
+		known: make(map[string]struct{}),
+	}
+
+	if path == "" {
+		return q, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return q, nil
+		}
+
+		return nil, errors.Wrap(err, "error opening review queue", logan.F{"path": path})
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, errors.Wrap(err, "error decoding review queue entry", logan.F{"path": path})
+		}
+
+		q.add(entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "error reading review queue", logan.F{"path": path})
+	}
+
+	return q, nil
+}
+
+// Push adds the deposit to the queue. Returns false if deposit has been already queued.
+func (q *Queue) Push(entry Entry) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.known[key(entry)]; ok {
+		return false, nil
+	}
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+
+	if q.path != "" {
+		if err := q.persist(entry); err != nil {
+			return false, err
+		}
+	}
+
+	q.add(entry)
+	return true, nil
+}
+
+// List returns all queued deposits in the order they were added.
+func (q *Queue) List() []Entry {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return append([]Entry(nil), q.entries...)
+}
+
+func (q *Queue) persist(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "error encoding review queue entry")
+	}
+
+	file, err := os.OpenFile(q.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "error opening review queue", logan.F{"path": q.path})
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "error writing review queue entry", logan.F{"path": q.path})
+	}
+
+	return errors.Wrap(file.Sync(), "error syncing review queue", logan.F{"path": q.path})
+}
+
+func (q *Queue) add(entry Entry) {
+	q.known[key(entry)] = struct{}{}
+	q.entries = append(q.entries, entry)
+}
+
+func key(entry Entry) string {
+	return entry.Tx + ":" + entry.EventId
+}
@@ -0,0 +1,174 @@
+package catchup
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	goerr "errors"
+	"sync"
+	"time"
+
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+var (
+	ErrJobNotFound = goerr.New("catchup job not found")
+	ErrJobRunning  = goerr.New("another catchup job is running")
+)
+
+// keepFinishedJobs is the amount of the last finished jobs kept to be queried, older ones are dropped
+const keepFinishedJobs = 100
+
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobDone      JobStatus = "done"
+	JobFailed    JobStatus = "failed"
+	JobCancelled JobStatus = "cancelled"
+)
+
+// Job is the snapshot of the catchup job state.
+type Job struct {
+	ID         string
+	Range      Range
+	Status     JobStatus
+	Progress   Progress
+	Err        error
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+type job struct {
+	Job
+	progress *Progress
+	cancel   context.CancelFunc
+}
+
+// Jobs runs the catchup jobs inside the running service. Only one job can be running at the same time.
+type Jobs struct {
+	log     *logan.Entry
+	service *Service
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	running string
+	// finished are the IDs of the kept finished jobs in the order they were finished
+	finished []string
+}
+
+func NewJobs(log *logan.Entry, service *Service) *Jobs {
+	return &Jobs{
+		log:     log,
+		service: service,
+		jobs:    make(map[string]*job),
+	}
+}
+
+// Start runs the catchup job in background. Returns ErrJobRunning if another job has not finished yet.
+func (j *Jobs) Start(r Range) (Job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.running != "" {
+		return Job{}, ErrJobRunning
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := &job{
+		Job: Job{
+			ID:        newJobID(),
+			Range:     r,
+			Status:    JobRunning,
+			StartedAt: time.Now().UTC(),
+		},
+		progress: &Progress{},
+		cancel:   cancel,
+	}
+
+	j.jobs[started.ID] = started
+	j.running = started.ID
+
+	go j.run(ctx, started)
+
+	return j.snapshot(started), nil
+}
+
+func (j *Jobs) Get(id string) (Job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	found, ok := j.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+
+	return j.snapshot(found), nil
+}
+
+// Cancel stops the running job. Finished jobs are returned as is.
+func (j *Jobs) Cancel(id string) (Job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	found, ok := j.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+
+	if found.Status == JobRunning {
+		found.cancel()
+		found.Status = JobCancelled
+	}
+
+	return j.snapshot(found), nil
+}
+
+func (j *Jobs) run(ctx context.Context, started *job) {
+	log := j.log.WithField("catchup_job", started.ID)
+	log.Info("Starting catchup job")
+
+	err := j.service.Run(ctx, started.Range, started.progress)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	started.cancel()
+	started.FinishedAt = time.Now().UTC()
+	j.running = ""
+	j.keepFinished(started.ID)
+
+	switch {
+	case started.Status == JobCancelled:
+		log.Info("Catchup job cancelled")
+	case err != nil:
+		started.Status = JobFailed
+		started.Err = err
+		log.WithError(err).Error("catchup job failed")
+	default:
+		started.Status = JobDone
+		log.Info("Catchup job finished")
+	}
+}
+
+func (j *Jobs) keepFinished(id string) {
+	j.finished = append(j.finished, id)
+
+	for len(j.finished) > keepFinishedJobs {
+		delete(j.jobs, j.finished[0])
+		j.finished = j.finished[1:]
+	}
+}
+
+func (j *Jobs) snapshot(found *job) Job {
+	snapshot := found.Job
+	snapshot.Progress = found.progress.Load()
+	return snapshot
+}
+
+func newJobID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
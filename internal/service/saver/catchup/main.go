@@ -3,6 +3,7 @@ package catchup
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/olegfomenko/solana-go"
 	"github.com/olegfomenko/solana-go/rpc"
@@ -22,17 +23,45 @@ type Service struct {
 	fromTx    solana.Signature
 }
 
-func NewService(cfg config.Config) *Service {
+func NewService(cfg config.Config, processor *saver.TxProcessor) *Service {
 	return &Service{
 		log:       cfg.Log(),
 		solana:    cfg.SolanaRPC(),
-		processor: saver.NewTxProcessor(cfg),
+		processor: processor,
 
 		programId: cfg.ListenConf().ProgramId,
 		fromTx:    cfg.ListenConf().FromTx,
 	}
 }
 
+// Range limits the catchupped bridge transactions. Transactions are catchupped from the latest to the oldest one.
+type Range struct {
+	// FromTx is the oldest transaction to catchup (inclusive). Slots are ignored if set.
+	FromTx solana.Signature
+	// FromSlot and ToSlot are the slots of the oldest and latest transactions to catchup (inclusive).
+	// ToSlot is unlimited if zero.
+	FromSlot uint64
+	ToSlot   uint64
+}
+
+// Progress counts the catchupped signature pages, transactions, found and broadcasted deposits.
+// Counters are updated atomically and can be read during the catchup.
+type Progress struct {
+	Pages        uint64
+	Transactions uint64
+	Deposits     uint64
+	Broadcasted  uint64
+}
+
+func (p *Progress) Load() Progress {
+	return Progress{
+		Pages:        atomic.LoadUint64(&p.Pages),
+		Transactions: atomic.LoadUint64(&p.Transactions),
+		Deposits:     atomic.LoadUint64(&p.Deposits),
+		Broadcasted:  atomic.LoadUint64(&p.Broadcasted),
+	}
+}
+
 // Catchup will list all transactions from last to specified in config and stored in l.fromTx
 func (s *Service) Catchup(ctx context.Context) error {
 	s.log.Info("Starting catchup")
@@ -40,24 +69,27 @@ func (s *Service) Catchup(ctx context.Context) error {
 		return nil
 	}
 
+	return s.Run(ctx, Range{FromTx: s.fromTx}, &Progress{})
+}
+
+// Run catchups the bridge transactions in the range updating the progress.
+func (s *Service) Run(ctx context.Context, r Range, progress *Progress) error {
 	var start solana.Signature
 	for {
-		last, err := s.catchupFrom(ctx, start)
+		last, done, err := s.catchupFrom(ctx, start, r, progress)
 		if err != nil {
 			return errors.Wrap(err, fmt.Sprintf("error catchupping history from %s", start))
 		}
 
-		if s.fromTx.Equals(last) {
-			break
+		if done {
+			return nil
 		}
 
 		start = last
 	}
-
-	return nil
 }
 
-func (s *Service) catchupFrom(ctx context.Context, start solana.Signature) (solana.Signature, error) {
+func (s *Service) catchupFrom(ctx context.Context, start solana.Signature, r Range, progress *Progress) (solana.Signature, bool, error) {
 	s.log.Info(fmt.Sprintf("Catchupping history from %s", start))
 
 	signatures, err := s.solana.GetSignaturesForAddressWithOpts(ctx, s.programId, &rpc.GetSignaturesForAddressOpts{
@@ -66,38 +98,59 @@ func (s *Service) catchupFrom(ctx context.Context, start solana.Signature) (sola
 	})
 
 	if err != nil {
-		return solana.Signature{}, errors.Wrap(err, "error getting txs")
+		return solana.Signature{}, false, errors.Wrap(err, "error getting txs")
 	}
 
+	atomic.AddUint64(&progress.Pages, 1)
+
 	for _, sig := range signatures {
-		s.log.Debug("Checking tx: " + sig.Signature.String())
-		tx, err := service.GetTransaction(ctx, s.solana, sig.Signature)
-		if err != nil {
-			s.log.WithError(err).Error("failed to get transaction " + sig.Signature.String())
+		if err := ctx.Err(); err != nil {
+			return solana.Signature{}, false, err
+		}
 
-			if s.fromTx.Equals(sig.Signature) {
-				return sig.Signature, nil
+		if r.FromTx.IsZero() {
+			if r.ToSlot != 0 && sig.Slot > r.ToSlot {
+				continue
 			}
 
-			continue
-		}
-
-		if tx == nil {
-			continue
+			if sig.Slot < r.FromSlot {
+				return sig.Signature, true, nil
+			}
 		}
 
-		if err = s.processor.ProcessTransaction(ctx, sig.Signature, tx); err != nil {
-			s.log.WithError(err).Error("failed to process transaction " + sig.Signature.String())
-		}
+		s.processSignature(ctx, sig.Signature, progress)
 
-		if s.fromTx.Equals(sig.Signature) {
-			return sig.Signature, nil
+		if !r.FromTx.IsZero() && r.FromTx.Equals(sig.Signature) {
+			return sig.Signature, true, nil
 		}
 	}
 
 	if len(signatures) == 0 {
-		return s.fromTx, nil
+		return solana.Signature{}, true, nil
+	}
+
+	return signatures[len(signatures)-1].Signature, false, nil
+}
+
+func (s *Service) processSignature(ctx context.Context, sig solana.Signature, progress *Progress) {
+	s.log.Debug("Checking tx: " + sig.String())
+	atomic.AddUint64(&progress.Transactions, 1)
+
+	tx, err := service.GetTransaction(ctx, s.solana, sig)
+	if err != nil {
+		s.log.WithError(err).Error("failed to get transaction " + sig.String())
+		return
+	}
+
+	if tx == nil {
+		return
 	}
 
-	return signatures[len(signatures)-1].Signature, nil
+	result, err := s.processor.ProcessTransaction(ctx, sig, tx)
+	atomic.AddUint64(&progress.Deposits, uint64(result.Deposits))
+	atomic.AddUint64(&progress.Broadcasted, uint64(result.Broadcasted))
+
+	if err != nil {
+		s.log.WithError(err).Error("failed to process transaction " + sig.String())
+	}
 }
@@ -0,0 +1,112 @@
+package saver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/rarimo/sol-saver-svc/internal/config"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/sol-saver-svc/internal/service/voter"
+	"github.com/rarimo/solana-program-go/contracts/bridge"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Decoder decodes the bridge program deposits of the transactions into core messages without broadcasting them.
+type Decoder struct {
+	program   solana.PublicKey
+	operators map[bridge.Instruction]IOperator
+	logs      *voter.LogsVerifier
+}
+
+func NewDecoder(cfg config.Config) *Decoder {
+	quorum := service.NewQuorum(cfg.Log(), 1, cfg.SolanaRPC())
+	bundles := voter.NewBundleVerifier(cfg)
+
+	return &Decoder{
+		program: cfg.ListenConf().ProgramId,
+		logs:    voter.NewLogsVerifier(cfg),
+		operators: map[bridge.Instruction]IOperator{
+			bridge.InstructionDepositNative: voter.NewNativeOperator(cfg.ListenConf().Chain, cfg.Log(), cfg.Cosmos(), cfg.TokenManager(), bundles),
+			bridge.InstructionDepositFT:     voter.NewFTOperator(cfg.ListenConf().Chain, cfg.Log(), quorum, cfg.Cosmos(), cfg.TokenManager(), bundles),
+			bridge.InstructionDepositNFT:    voter.NewNFTOperator(cfg.ListenConf().Chain, cfg.Log(), quorum, cfg.Cosmos(), cfg.TokenManager(), cfg.NFTConf(), cfg.MetadataFetcher(), bundles),
+		},
+	}
+}
+
+// DecodeTransaction decodes all bridge program deposits of the transaction in the order of instructions.
+// If the transaction balance changes do not back the deposits, all of them are rejected.
+func (d *Decoder) DecodeTransaction(ctx context.Context, sig solana.Signature, tx *service.Transaction) []Deposit {
+	accounts := tx.Message.AccountKeys
+	balanceErr := voter.VerifyBalances(d.program, tx)
+
+	var deposits []Deposit
+
+	for index, instruction := range tx.Message.Instructions {
+		if int(instruction.ProgramIDIndex) >= len(accounts) || accounts[instruction.ProgramIDIndex] != d.program || len(instruction.Data) == 0 {
+			continue
+		}
+
+		code := bridge.Instruction(instruction.Data[DataInstructionCodeIndex])
+
+		operator, ok := d.operators[code]
+		if !ok {
+			continue
+		}
+
+		if balanceErr != nil {
+			deposits = append(deposits, Deposit{
+				EventId:     index,
+				Instruction: code,
+				Status:      DepositRejected,
+				Err:         errors.Wrap(balanceErr, "deposit is not backed by balance changes"),
+			})
+			continue
+		}
+
+		deposits = append(deposits, d.decodeDeposit(ctx, sig, tx, index, code, operator))
+	}
+
+	return deposits
+}
+
+func (d *Decoder) decodeDeposit(ctx context.Context, sig solana.Signature, tx *service.Transaction, index int, code bridge.Instruction, operator IOperator) Deposit {
+	deposit := Deposit{
+		EventId:     index,
+		Instruction: code,
+	}
+
+	instruction := tx.Message.Instructions[index]
+
+	if err := voter.VerifyAccounts(tx, instruction); err != nil {
+		deposit.Status = DepositRejected
+		deposit.Err = errors.Wrap(err, "invalid accounts")
+		return deposit
+	}
+
+	if err := d.logs.Verify(tx, index); err != nil {
+		deposit.Status = DepositRejected
+		deposit.Err = errors.Wrap(err, "logs mismatch")
+		return deposit
+	}
+
+	msg, err := operator.GetMessage(ctx, tx, instruction)
+	if err != nil {
+		deposit.Status = DepositFailed
+		deposit.Err = err
+
+		if reason, ok := voter.RefundReason(err); ok {
+			deposit.Status = DepositRefund
+			deposit.RefundReason = reason
+		}
+
+		return deposit
+	}
+
+	msg.Tx = sig.String()
+	msg.EventId = fmt.Sprint(index)
+
+	deposit.Status = DepositDecoded
+	deposit.Msg = msg
+	return deposit
+}
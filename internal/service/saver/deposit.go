@@ -0,0 +1,29 @@
+package saver
+
+import (
+	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
+	"github.com/rarimo/solana-program-go/contracts/bridge"
+)
+
+type DepositStatus string
+
+const (
+	// DepositDecoded is the deposit ready to be broadcasted to core
+	DepositDecoded DepositStatus = "decoded"
+	// DepositRejected is the instruction that does not match the transaction accounts, logs or balance changes
+	DepositRejected DepositStatus = "rejected"
+	// DepositRefund is the deposit that can never be bridged and should be refunded
+	DepositRefund DepositStatus = "refund"
+	// DepositFailed is the deposit that could not be decoded due to the temporary error
+	DepositFailed DepositStatus = "failed"
+)
+
+// Deposit is the bridge program deposit instruction decoded from the Solana transaction.
+type Deposit struct {
+	EventId      int
+	Instruction  bridge.Instruction
+	Status       DepositStatus
+	Msg          *oracletypes.MsgCreateTransferOp
+	RefundReason string
+	Err          error
+}
@@ -0,0 +1,128 @@
+package saver
+
+import (
+	goerr "errors"
+	"sync"
+
+	"github.com/olegfomenko/solana-go"
+)
+
+// ErrCursorExpired is returned when the requested feed position is no longer kept in the feed buffer.
+var ErrCursorExpired = goerr.New("cursor expired")
+
+// FeedEvent is the decoded deposit with its broadcast result.
+type FeedEvent struct {
+	// Seq is the position of the event in the feed
+	Seq     uint64
+	Slot    uint64
+	Tx      solana.Signature
+	Deposit Deposit
+	// BroadcastErr is the error of broadcasting the decoded deposit to core
+	BroadcastErr error
+	// Pending is set if the decoded deposit is kept pending because the saver is paused,
+	// the deposit is published again when it is broadcasted
+	Pending bool
+}
+
+// Feed keeps the last decoded deposits in the ring buffer and notifies subscribers about new ones.
+type Feed struct {
+	mu     sync.RWMutex
+	events []FeedEvent
+	size   int
+	// seq of the last published event, events are numbered from 1
+	seq    uint64
+	notify chan struct{}
+}
+
+// NewFeed creates the feed keeping the last size events, size should be positive.
+func NewFeed(size int) *Feed {
+	return &Feed{
+		events: make([]FeedEvent, 0, size),
+		size:   size,
+		notify: make(chan struct{}),
+	}
+}
+
+func (f *Feed) Publish(event FeedEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	event.Seq = f.seq
+
+	if len(f.events) < f.size {
+		f.events = append(f.events, event)
+	} else {
+		f.events[(f.seq-1)%uint64(f.size)] = event
+	}
+
+	close(f.notify)
+	f.notify = make(chan struct{})
+}
+
+// Head returns the position of the last published event.
+func (f *Feed) Head() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.seq
+}
+
+// FeedCursor is the position of the deposit event in the feed.
+type FeedCursor struct {
+	Slot uint64
+	Tx   solana.Signature
+	// EventId is the deposit event id, if <nil> the cursor points to the last event of the transaction
+	EventId *int
+	// Seq is the event position in the feed, zero if unknown
+	Seq uint64
+}
+
+// Find returns the position of the event the cursor points to. The event at the cursor Seq is used if it
+// matches the cursor, otherwise (e.g. the cursor was issued before the restart) the last matching event is used.
+func (f *Feed) Find(cursor FeedCursor) (uint64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	matches := func(event FeedEvent) bool {
+		return event.Slot == cursor.Slot && event.Tx == cursor.Tx &&
+			(cursor.EventId == nil || event.Deposit.EventId == *cursor.EventId)
+	}
+
+	if cursor.Seq != 0 && cursor.Seq <= f.seq && f.seq-cursor.Seq < uint64(len(f.events)) {
+		if matches(f.events[(cursor.Seq-1)%uint64(f.size)]) {
+			return cursor.Seq, nil
+		}
+	}
+
+	var seq uint64
+	for _, event := range f.events {
+		if matches(event) && event.Seq > seq {
+			seq = event.Seq
+		}
+	}
+
+	if seq == 0 {
+		return 0, ErrCursorExpired
+	}
+
+	return seq, nil
+}
+
+// After returns the events published after the position and the channel closed on the next publish.
+// Returns ErrCursorExpired if some events after the position were already evicted from the buffer.
+func (f *Feed) After(seq uint64) ([]FeedEvent, <-chan struct{}, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if seq > f.seq || f.seq-seq > uint64(len(f.events)) {
+		return nil, nil, ErrCursorExpired
+	}
+
+	events := make([]FeedEvent, 0, f.seq-seq)
+	for next := seq + 1; next <= f.seq; next++ {
+		events = append(events, f.events[(next-1)%uint64(f.size)])
+	}
+
+	return events, f.notify, nil
+}
@@ -0,0 +1,199 @@
+package saver
+
+import (
+	"testing"
+
+	"github.com/olegfomenko/solana-go"
+)
+
+func publish(f *Feed, slots ...uint64) {
+	for _, slot := range slots {
+		f.Publish(FeedEvent{Slot: slot, Tx: solana.Signature{byte(slot)}})
+	}
+}
+
+func seqs(events []FeedEvent) []uint64 {
+	result := make([]uint64, 0, len(events))
+	for _, e := range events {
+		result = append(result, e.Seq)
+	}
+	return result
+}
+
+func equal(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFeedAfter(t *testing.T) {
+	cases := []struct {
+		name      string
+		size      int
+		published int
+		after     uint64
+		want      []uint64
+		expired   bool
+	}{
+		{name: "empty feed", size: 3, published: 0, after: 0, want: []uint64{}},
+		{name: "from start", size: 3, published: 2, after: 0, want: []uint64{1, 2}},
+		{name: "head", size: 3, published: 2, after: 2, want: []uint64{}},
+		{name: "full buffer", size: 3, published: 3, after: 0, want: []uint64{1, 2, 3}},
+		{name: "wrapped around", size: 3, published: 5, after: 2, want: []uint64{3, 4, 5}},
+		{name: "wrapped around partially read", size: 3, published: 5, after: 3, want: []uint64{4, 5}},
+		{name: "wrapped around twice", size: 3, published: 7, after: 5, want: []uint64{6, 7}},
+		{name: "evicted", size: 3, published: 5, after: 1, expired: true},
+		{name: "evicted from start", size: 3, published: 4, after: 0, expired: true},
+		{name: "ahead of head", size: 3, published: 2, after: 3, expired: true},
+		{name: "single event buffer", size: 1, published: 4, after: 3, want: []uint64{4}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := NewFeed(c.size)
+			for i := 1; i <= c.published; i++ {
+				publish(f, uint64(i))
+			}
+
+			events, notify, err := f.After(c.after)
+			if c.expired {
+				if err != ErrCursorExpired {
+					t.Fatalf("expected ErrCursorExpired, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !equal(seqs(events), c.want) {
+				t.Fatalf("expected events %v, got %v", c.want, seqs(events))
+			}
+
+			for _, e := range events {
+				if e.Slot != e.Seq {
+					t.Fatalf("event %d has slot of event %d", e.Seq, e.Slot)
+				}
+			}
+
+			select {
+			case <-notify:
+				t.Fatal("notify channel closed before publish")
+			default:
+			}
+
+			publish(f, uint64(c.published+1))
+
+			select {
+			case <-notify:
+			default:
+				t.Fatal("notify channel is not closed on publish")
+			}
+		})
+	}
+}
+
+func TestFeedFind(t *testing.T) {
+	f := NewFeed(5)
+
+	deposit := func(slot uint64, eventId int) {
+		f.Publish(FeedEvent{Slot: slot, Tx: solana.Signature{byte(slot)}, Deposit: Deposit{EventId: eventId}})
+	}
+
+	// transaction with several deposits is published as several events,
+	// deposit kept pending is published again when it is broadcasted
+	deposit(1, 0)
+	deposit(2, 0)
+	deposit(2, 1)
+	deposit(2, 2)
+	deposit(3, 0)
+	deposit(2, 1)
+
+	id := func(eventId int) *int { return &eventId }
+
+	cases := []struct {
+		name    string
+		cursor  FeedCursor
+		want    uint64
+		expired bool
+	}{
+		{name: "last event of the transaction", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}}, want: 6},
+		{name: "first event of the transaction", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(0)}, want: 2},
+		{name: "last event of the deposit", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(1)}, want: 6},
+		{name: "event at seq", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(1), Seq: 3}, want: 3},
+		{name: "later event at seq", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(1), Seq: 6}, want: 6},
+		{name: "seq of another deposit", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(1), Seq: 4}, want: 6},
+		{name: "seq ahead of head", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(2), Seq: 100}, want: 4},
+		{name: "evicted seq", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(0), Seq: 1}, want: 2},
+		{name: "latest event", cursor: FeedCursor{Slot: 3, Tx: solana.Signature{3}, EventId: id(0), Seq: 5}, want: 5},
+		{name: "unknown event", cursor: FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(3)}, expired: true},
+		{name: "evicted", cursor: FeedCursor{Slot: 1, Tx: solana.Signature{1}, EventId: id(0), Seq: 1}, expired: true},
+		{name: "slot mismatch", cursor: FeedCursor{Slot: 4, Tx: solana.Signature{3}}, expired: true},
+		{name: "unknown transaction", cursor: FeedCursor{Slot: 3, Tx: solana.Signature{9}}, expired: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			seq, err := f.Find(c.cursor)
+			if c.expired {
+				if err != ErrCursorExpired {
+					t.Fatalf("expected ErrCursorExpired, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if seq != c.want {
+				t.Fatalf("expected seq %d, got %d", c.want, seq)
+			}
+		})
+	}
+
+	t.Run("resume in the middle of the transaction", func(t *testing.T) {
+		seq, err := f.Find(FeedCursor{Slot: 2, Tx: solana.Signature{2}, EventId: id(0), Seq: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		events, _, err := f.After(seq)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !equal(seqs(events), []uint64{3, 4, 5, 6}) {
+			t.Fatalf("expected remaining deposits of the transaction, got %v", seqs(events))
+		}
+
+		if events[0].Deposit.EventId != 1 || events[1].Deposit.EventId != 2 {
+			t.Fatalf("expected deposits 1 and 2 of the transaction, got %d and %d", events[0].Deposit.EventId, events[1].Deposit.EventId)
+		}
+	})
+}
+
+func TestFeedPublish(t *testing.T) {
+	f := NewFeed(2)
+	publish(f, 1, 2, 3)
+
+	if head := f.Head(); head != 3 {
+		t.Fatalf("expected head 3, got %d", head)
+	}
+
+	if len(f.events) != 2 {
+		t.Fatalf("expected 2 buffered events, got %d", len(f.events))
+	}
+
+	// the oldest event is overwritten in place
+	if f.events[0].Seq != 3 || f.events[1].Seq != 2 {
+		t.Fatalf("unexpected buffer content %v", seqs(f.events))
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/rarimo/saver-grpc-lib/metrics"
 	"github.com/rarimo/sol-saver-svc/internal/config"
 	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/sol-saver-svc/internal/service/health"
 	"github.com/rarimo/sol-saver-svc/internal/service/saver"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
@@ -22,16 +23,18 @@ type Service struct {
 	log       *logan.Entry
 	processor *saver.TxProcessor
 	solana    *rpc.Client
+	health    *health.Monitor
 
 	programId  solana.PublicKey
 	wsEndpoint string
 }
 
-func NewService(cfg config.Config) *Service {
+func NewService(cfg config.Config, processor *saver.TxProcessor) *Service {
 	return &Service{
 		log:        cfg.Log(),
-		processor:  saver.NewTxProcessor(cfg),
+		processor:  processor,
 		solana:     cfg.SolanaRPC(),
+		health:     cfg.Health(),
 		programId:  cfg.ListenConf().ProgramId,
 		wsEndpoint: cfg.SolanaWSEndpoint(),
 	}
@@ -45,6 +48,8 @@ func (s *Service) listen(ctx context.Context) (bool, error) {
 	wsCtx, wsCancel := context.WithCancel(ctx)
 	defer wsCancel()
 
+	s.health.SetWebsocket(false)
+
 	client, err := ws.Connect(wsCtx, s.wsEndpoint)
 	if err != nil {
 		return false, errors.Wrap(err, "error opening solana websocket")
@@ -63,6 +68,7 @@ func (s *Service) listen(ctx context.Context) (bool, error) {
 	defer client.Close()
 
 	metrics.WebsocketMetric.Set(metrics.WebsocketAvailable)
+	s.health.SetWebsocket(true)
 
 	for {
 		select {
@@ -72,6 +78,7 @@ func (s *Service) listen(ctx context.Context) (bool, error) {
 			got, err := sub.Recv()
 			if err != nil {
 				metrics.WebsocketMetric.Set(metrics.WebsocketDisconnected)
+				s.health.SetWebsocket(false)
 				return false, errors.Wrap(err, "failed to receive transaction")
 			}
 
@@ -85,7 +92,7 @@ func (s *Service) listen(ctx context.Context) (bool, error) {
 				continue
 			}
 
-			if err = s.processor.ProcessTransaction(ctx, got.Value.Signature, tx); err != nil {
+			if _, err = s.processor.ProcessTransaction(ctx, got.Value.Signature, tx); err != nil {
 				s.log.WithError(err).Error("failed to process transaction " + got.Value.Signature.String())
 			}
 		}
@@ -3,12 +3,16 @@ package saver
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync"
 
 	"github.com/olegfomenko/solana-go"
 	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
 	"github.com/rarimo/saver-grpc-lib/broadcaster"
 	"github.com/rarimo/sol-saver-svc/internal/config"
 	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/sol-saver-svc/internal/service/pause"
+	"github.com/rarimo/sol-saver-svc/internal/service/review"
 	"github.com/rarimo/sol-saver-svc/internal/service/voter"
 	"github.com/rarimo/solana-program-go/contracts/bridge"
 	"gitlab.com/distributed_lab/logan/v3"
@@ -20,51 +24,224 @@ const (
 )
 
 type IOperator interface {
-	GetMessage(ctx context.Context, accounts []solana.PublicKey, instruction solana.CompiledInstruction) (*oracletypes.MsgCreateTransferOp, error)
+	GetMessage(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction) (*oracletypes.MsgCreateTransferOp, error)
 }
 
 type TxProcessor struct {
+	*Decoder
+
 	log         *logan.Entry
-	program     solana.PublicKey
-	operators   map[bridge.Instruction]IOperator
 	broadcaster broadcaster.Broadcaster
+	review      *review.Queue
+	pending     *pause.Pending
+	feed        *Feed
+
+	// flush serializes broadcasting of the pending deposits
+	flush sync.Mutex
 }
 
-func NewTxProcessor(cfg config.Config) *TxProcessor {
-	return &TxProcessor{
+// NewTxProcessor creates the processor publishing processed deposits to the feed (feed is optional).
+// Deposits processed while the saver is paused are kept in the pending queue and broadcasted when it is resumed.
+// Without the pending queue (one-shot runs that can not flush it) deposits fail with pause.ErrPaused instead.
+func NewTxProcessor(cfg config.Config, feed *Feed, pending *pause.Pending) *TxProcessor {
+	p := &TxProcessor{
+		Decoder:     NewDecoder(cfg),
 		log:         cfg.Log(),
-		program:     cfg.ListenConf().ProgramId,
-		broadcaster: cfg.Broadcaster(),
-		operators: map[bridge.Instruction]IOperator{
-			bridge.InstructionDepositNative: voter.NewNativeOperator(cfg.ListenConf().Chain, cfg.Log(), cfg.Cosmos()),
-			bridge.InstructionDepositFT:     voter.NewFTOperator(cfg.ListenConf().Chain, cfg.Log(), cfg.Cosmos()),
-			bridge.InstructionDepositNFT:    voter.NewNFTOperator(cfg.ListenConf().Chain, cfg.SolanaRPC(), cfg.Cosmos()),
-		},
+		broadcaster: pause.NewBroadcaster(cfg.Broadcaster(), cfg.Pause(), pause.Saver),
+		review:      cfg.ReviewQueue(),
+		pending:     pending,
+		feed:        feed,
+	}
+
+	if pending != nil {
+		cfg.Pause().OnResume(pause.Saver, func() {
+			p.FlushPending(context.Background())
+		})
 	}
+
+	return p
+}
+
+// ProcessResult is the amount of deposits found in the transaction, broadcasted to core
+// and kept pending because the saver is paused.
+type ProcessResult struct {
+	Deposits    int
+	Broadcasted int
+	Pending     int
 }
 
-func (s *TxProcessor) ProcessTransaction(ctx context.Context, sig solana.Signature, tx *solana.Transaction) error {
-	accounts := tx.Message.AccountKeys
+func (s *TxProcessor) ProcessTransaction(ctx context.Context, sig solana.Signature, tx *service.Transaction) (ProcessResult, error) {
 	s.log.Debug("Parsing transaction " + sig.String())
 
-	for index, instruction := range tx.Message.Instructions {
-		if accounts[instruction.ProgramIDIndex] == s.program {
-			if operator, ok := s.operators[bridge.Instruction(instruction.Data[DataInstructionCodeIndex])]; ok {
-				msg, err := operator.GetMessage(ctx, service.GetInstructionAccounts(accounts, instruction.Accounts), instruction)
-				if err != nil {
-					return errors.Wrap(err, "error getting message")
-				}
-
-				msg.Creator = s.broadcaster.Sender()
-				msg.Tx = sig.String()
-				msg.EventId = fmt.Sprint(index)
-
-				if err := s.broadcaster.BroadcastTx(ctx, msg); err != nil {
-					return errors.Wrap(err, "error broadcasting tx")
-				}
+	var result ProcessResult
+
+	deposits := s.DecodeTransaction(ctx, sig, tx)
+
+	result.Deposits = len(deposits)
+
+	for _, deposit := range deposits {
+		log := s.log.WithError(deposit.Err).WithFields(logan.F{"tx": sig.String(), "event_id": deposit.EventId})
+
+		switch deposit.Status {
+		case DepositRejected:
+			log.Error("deposit rejected")
+			s.publish(FeedEvent{Slot: tx.Slot, Tx: sig, Deposit: deposit})
+			continue
+		case DepositRefund:
+			if err := s.flagForRefund(sig, deposit.EventId, deposit.RefundReason, deposit.Err); err != nil {
+				return result, errors.Wrap(err, "error flagging deposit for refund")
 			}
+			s.publish(FeedEvent{Slot: tx.Slot, Tx: sig, Deposit: deposit})
+			continue
+		case DepositFailed:
+			s.publish(FeedEvent{Slot: tx.Slot, Tx: sig, Deposit: deposit})
+			return result, errors.Wrap(deposit.Err, "error getting message")
 		}
+
+		msg := deposit.Msg
+		msg.Creator = s.broadcaster.Sender()
+
+		err := s.broadcaster.BroadcastTx(ctx, msg)
+
+		if errors.Cause(err) == pause.ErrPaused && s.pending != nil {
+			if err := s.keepPending(tx.Slot, deposit.Instruction, msg); err != nil {
+				s.publish(FeedEvent{Slot: tx.Slot, Tx: sig, Deposit: deposit, BroadcastErr: err})
+				return result, errors.Wrap(err, "error keeping deposit pending")
+			}
+
+			s.publish(FeedEvent{Slot: tx.Slot, Tx: sig, Deposit: deposit, Pending: true})
+			result.Pending++
+			continue
+		}
+
+		s.publish(FeedEvent{Slot: tx.Slot, Tx: sig, Deposit: deposit, BroadcastErr: err})
+
+		if err != nil {
+			return result, errors.Wrap(err, "error broadcasting tx")
+		}
+
+		result.Broadcasted++
+	}
+
+	return result, nil
+}
+
+func (s *TxProcessor) flagForRefund(sig solana.Signature, index int, reason string, cause error) error {
+	log := s.log.WithError(cause).WithFields(logan.F{"tx": sig.String(), "event_id": index, "reason": reason})
+
+	queued, err := s.review.Push(review.Entry{
+		Tx:      sig.String(),
+		EventId: fmt.Sprint(index),
+		Reason:  reason,
+		Error:   cause.Error(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if !queued {
+		log.Debug("deposit already queued for refund review")
+		return nil
+	}
+
+	voter.FlaggedDepositsMetric.WithLabelValues(reason).Inc()
+	log.Error("deposit flagged for refund")
+	return nil
+}
+
+func (s *TxProcessor) keepPending(slot uint64, instruction bridge.Instruction, msg *oracletypes.MsgCreateTransferOp) error {
+	queued, err := s.pending.Push(slot, uint8(instruction), msg)
+	if err != nil {
+		return err
+	}
+
+	if queued {
+		s.log.WithFields(logan.F{"tx": msg.Tx, "event_id": msg.EventId}).Warn("saver is paused, deposit kept pending")
 	}
 
 	return nil
 }
+
+// FlushPending broadcasts the deposits kept pending while the saver was paused in the order they were observed
+// and publishes the broadcasted ones to the feed. Deposits failed to broadcast stay pending till the next flush.
+func (s *TxProcessor) FlushPending(ctx context.Context) {
+	if s.pending == nil {
+		return
+	}
+
+	s.flush.Lock()
+	defer s.flush.Unlock()
+
+	deposits, err := s.pending.List()
+	if err != nil {
+		s.log.WithError(err).Error("failed to load pending deposits")
+		return
+	}
+
+	for _, deposit := range deposits {
+		log := s.log.WithFields(logan.F{"tx": deposit.Tx, "event_id": deposit.EventId})
+
+		msg, err := deposit.Message()
+		if err != nil {
+			log.WithError(err).Error("failed to decode pending deposit")
+			continue
+		}
+
+		err = s.broadcaster.BroadcastTx(ctx, msg)
+		if errors.Cause(err) == pause.ErrPaused {
+			log.Info("saver is paused, stopped broadcasting pending deposits")
+			return
+		}
+
+		if err != nil {
+			log.WithError(err).Error("failed to broadcast pending deposit")
+			continue
+		}
+
+		if err := s.pending.Remove(deposit.Tx, deposit.EventId); err != nil {
+			log.WithError(err).Error("failed to remove broadcasted pending deposit")
+			continue
+		}
+
+		log.Info("pending deposit broadcasted")
+		s.publishBroadcasted(deposit, msg)
+	}
+}
+
+// publishBroadcasted publishes the pending deposit broadcasted after the saver was resumed.
+func (s *TxProcessor) publishBroadcasted(deposit pause.PendingDeposit, msg *oracletypes.MsgCreateTransferOp) {
+	if s.feed == nil {
+		return
+	}
+
+	sig, err := solana.SignatureFromBase58(deposit.Tx)
+	if err != nil {
+		s.log.WithError(err).WithField("tx", deposit.Tx).Error("failed to parse pending deposit signature")
+		return
+	}
+
+	eventId, err := strconv.Atoi(deposit.EventId)
+	if err != nil {
+		s.log.WithError(err).WithField("event_id", deposit.EventId).Error("failed to parse pending deposit event id")
+		return
+	}
+
+	s.feed.Publish(FeedEvent{
+		Slot: deposit.Slot,
+		Tx:   sig,
+		Deposit: Deposit{
+			EventId:     eventId,
+			Instruction: bridge.Instruction(deposit.Instruction),
+			Status:      DepositDecoded,
+			Msg:         msg,
+		},
+	})
+}
+
+func (s *TxProcessor) publish(event FeedEvent) {
+	if s.feed == nil {
+		return
+	}
+
+	s.feed.Publish(event)
+}
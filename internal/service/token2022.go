@@ -0,0 +1,149 @@
+package service
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/olegfomenko/solana-go"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+var Token2022ProgramID = solana.MustPublicKeyFromBase58("TokenzQdBNbLqP5VEhdkAS6EPFLC1PUnBqCXEpoxuVc")
+
+type ExtensionType uint16
+
+// Token-2022 mint extensions that affect deposits
+const (
+	ExtensionUninitialized                 ExtensionType = 0
+	ExtensionTransferFeeConfig             ExtensionType = 1
+	ExtensionConfidentialTransferMint      ExtensionType = 4
+	ExtensionNonTransferable               ExtensionType = 9
+	ExtensionInterestBearingConfig         ExtensionType = 10
+	ExtensionPermanentDelegate             ExtensionType = 12
+	ExtensionTransferHook                  ExtensionType = 14
+	ExtensionConfidentialTransferFeeConfig ExtensionType = 16
+)
+
+const (
+	mintSize         = 82
+	tokenAccountSize = 165
+	accountTypeMint  = 1
+
+	tlvHeaderSize = 4
+
+	transferFeeSize       = 18
+	transferFeeConfigSize = 32 + 32 + 8 + 2*transferFeeSize
+	transferHookSize      = 32 + 32
+	permanentDelegateSize = 32
+
+	maxBasisPoints = 10000
+)
+
+// ParseMintExtensions returns the TLV encoded extensions of the Token-2022 mint account data.
+// Mint without extensions has the data of the classic SPL Token mint size.
+func ParseMintExtensions(data []byte) (map[ExtensionType][]byte, error) {
+	extensions := make(map[ExtensionType][]byte)
+
+	if len(data) == mintSize {
+		return extensions, nil
+	}
+
+	// Base mint is padded to the token account size to be distinguished by the account type
+	if len(data) <= tokenAccountSize {
+		return nil, errors.From(errors.New("invalid mint data length"), logan.F{"length": len(data)})
+	}
+
+	if data[tokenAccountSize] != accountTypeMint {
+		return nil, errors.From(errors.New("account is not a mint"), logan.F{"account_type": data[tokenAccountSize]})
+	}
+
+	for tlv := data[tokenAccountSize+1:]; len(tlv) >= tlvHeaderSize; {
+		extension := ExtensionType(binary.LittleEndian.Uint16(tlv[0:2]))
+		length := int(binary.LittleEndian.Uint16(tlv[2:4]))
+
+		if extension == ExtensionUninitialized {
+			break
+		}
+
+		if len(tlv) < tlvHeaderSize+length {
+			return nil, errors.From(errors.New("invalid extension length"), logan.F{"extension": extension})
+		}
+
+		extensions[extension] = tlv[tlvHeaderSize : tlvHeaderSize+length]
+		tlv = tlv[tlvHeaderSize+length:]
+	}
+
+	return extensions, nil
+}
+
+type TransferFee struct {
+	Epoch       uint64
+	MaximumFee  uint64
+	BasisPoints uint16
+}
+
+// Calculate returns the fee charged from the transfer of the provided amount.
+func (f TransferFee) Calculate(amount uint64) uint64 {
+	if f.BasisPoints == 0 || amount == 0 {
+		return 0
+	}
+
+	// fee = ceil(amount * basis points / 10000)
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(int64(f.BasisPoints)))
+	fee.Add(fee, big.NewInt(maxBasisPoints-1))
+	fee.Div(fee, big.NewInt(maxBasisPoints))
+
+	if !fee.IsUint64() || fee.Uint64() > f.MaximumFee {
+		return f.MaximumFee
+	}
+
+	return fee.Uint64()
+}
+
+// TransferFeeConfig contains the fee configurations that was effective before and after the Newer.Epoch.
+type TransferFeeConfig struct {
+	Older TransferFee
+	Newer TransferFee
+}
+
+func ParseTransferFeeConfig(data []byte) (*TransferFeeConfig, error) {
+	if len(data) < transferFeeConfigSize {
+		return nil, errors.New("invalid transfer fee config length")
+	}
+
+	// skipping authorities and withheld amount
+	fees := data[32+32+8:]
+
+	return &TransferFeeConfig{
+		Older: parseTransferFee(fees[:transferFeeSize]),
+		Newer: parseTransferFee(fees[transferFeeSize:]),
+	}, nil
+}
+
+func parseTransferFee(data []byte) TransferFee {
+	return TransferFee{
+		Epoch:       binary.LittleEndian.Uint64(data[0:8]),
+		MaximumFee:  binary.LittleEndian.Uint64(data[8:16]),
+		BasisPoints: binary.LittleEndian.Uint16(data[16:18]),
+	}
+}
+
+// ParseTransferHookProgram returns the transfer hook program or zero key if hook is not set.
+func ParseTransferHookProgram(data []byte) (solana.PublicKey, error) {
+	if len(data) < transferHookSize {
+		return solana.PublicKey{}, errors.New("invalid transfer hook length")
+	}
+
+	// skipping authority
+	return solana.PublicKeyFromBytes(data[32:64]), nil
+}
+
+// ParsePermanentDelegate returns the permanent delegate or zero key if it is not set.
+func ParsePermanentDelegate(data []byte) (solana.PublicKey, error) {
+	if len(data) < permanentDelegateSize {
+		return solana.PublicKey{}, errors.New("invalid permanent delegate length")
+	}
+
+	return solana.PublicKeyFromBytes(data[:32]), nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// mintData builds the Token-2022 mint account data with the provided TLV encoded extensions.
+func mintData(tlv ...[]byte) []byte {
+	data := make([]byte, tokenAccountSize+1)
+	data[tokenAccountSize] = accountTypeMint
+	for _, entry := range tlv {
+		data = append(data, entry...)
+	}
+	return data
+}
+
+func tlvEntry(extension ExtensionType, value []byte) []byte {
+	entry := make([]byte, tlvHeaderSize, tlvHeaderSize+len(value))
+	binary.LittleEndian.PutUint16(entry[0:2], uint16(extension))
+	binary.LittleEndian.PutUint16(entry[2:4], uint16(len(value)))
+	return append(entry, value...)
+}
+
+func TestParseMintExtensions(t *testing.T) {
+	hook := bytes.Repeat([]byte{1}, transferHookSize)
+	delegate := bytes.Repeat([]byte{2}, permanentDelegateSize)
+
+	notMint := mintData()
+	notMint[tokenAccountSize] = 2
+
+	cases := []struct {
+		name    string
+		data    []byte
+		want    map[ExtensionType][]byte
+		wantErr bool
+	}{
+		{name: "classic mint", data: make([]byte, mintSize), want: map[ExtensionType][]byte{}},
+		{name: "mint without extensions", data: mintData(), want: map[ExtensionType][]byte{}},
+		{name: "single extension", data: mintData(tlvEntry(ExtensionTransferHook, hook)), want: map[ExtensionType][]byte{ExtensionTransferHook: hook}},
+		{
+			name: "several extensions",
+			data: mintData(tlvEntry(ExtensionTransferHook, hook), tlvEntry(ExtensionNonTransferable, nil), tlvEntry(ExtensionPermanentDelegate, delegate)),
+			want: map[ExtensionType][]byte{ExtensionTransferHook: hook, ExtensionNonTransferable: {}, ExtensionPermanentDelegate: delegate},
+		},
+		{
+			name: "unknown extension is kept",
+			data: mintData(tlvEntry(ExtensionType(1000), []byte{1, 2, 3})),
+			want: map[ExtensionType][]byte{ExtensionType(1000): {1, 2, 3}},
+		},
+		{
+			name: "uninitialized extension ends the list",
+			data: mintData(tlvEntry(ExtensionPermanentDelegate, delegate), tlvEntry(ExtensionUninitialized, nil), tlvEntry(ExtensionTransferHook, hook)),
+			want: map[ExtensionType][]byte{ExtensionPermanentDelegate: delegate},
+		},
+		{
+			name: "trailing bytes shorter than the header are ignored",
+			data: mintData(tlvEntry(ExtensionPermanentDelegate, delegate), []byte{1, 0}),
+			want: map[ExtensionType][]byte{ExtensionPermanentDelegate: delegate},
+		},
+		{name: "truncated extension value", data: mintData(tlvEntry(ExtensionTransferHook, hook)[:tlvHeaderSize+10]), wantErr: true},
+		{name: "extension length exceeds data", data: mintData(tlvEntry(ExtensionPermanentDelegate, delegate), tlvEntry(ExtensionTransferHook, nil)[:2], []byte{0xff, 0xff}), wantErr: true},
+		{name: "empty data", data: nil, wantErr: true},
+		{name: "data between mint and account size", data: make([]byte, mintSize+1), wantErr: true},
+		{name: "data of account size", data: make([]byte, tokenAccountSize), wantErr: true},
+		{name: "account is not a mint", data: notMint, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			extensions, err := ParseMintExtensions(c.data)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", extensions)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(extensions) != len(c.want) {
+				t.Fatalf("expected %d extensions, got %d", len(c.want), len(extensions))
+			}
+
+			for extension, value := range c.want {
+				if !bytes.Equal(extensions[extension], value) {
+					t.Fatalf("extension %d: expected %x, got %x", extension, value, extensions[extension])
+				}
+			}
+		})
+	}
+}
+
+func TestParseTransferFeeConfig(t *testing.T) {
+	fee := func(epoch, maximum uint64, basisPoints uint16) []byte {
+		data := make([]byte, transferFeeSize)
+		binary.LittleEndian.PutUint64(data[0:8], epoch)
+		binary.LittleEndian.PutUint64(data[8:16], maximum)
+		binary.LittleEndian.PutUint16(data[16:18], basisPoints)
+		return data
+	}
+
+	data := append(make([]byte, 32+32+8), fee(1, 100, 50)...)
+	data = append(data, fee(2, 200, 75)...)
+
+	config, err := ParseTransferFeeConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &TransferFeeConfig{
+		Older: TransferFee{Epoch: 1, MaximumFee: 100, BasisPoints: 50},
+		Newer: TransferFee{Epoch: 2, MaximumFee: 200, BasisPoints: 75},
+	}
+
+	if !reflect.DeepEqual(config, want) {
+		t.Fatalf("expected %+v, got %+v", want, config)
+	}
+
+	if _, err := ParseTransferFeeConfig(data[:len(data)-1]); err == nil {
+		t.Fatal("expected error for truncated transfer fee config")
+	}
+}
+
+func TestTransferFeeCalculate(t *testing.T) {
+	cases := []struct {
+		name   string
+		fee    TransferFee
+		amount uint64
+		want   uint64
+	}{
+		{name: "zero basis points", fee: TransferFee{MaximumFee: 100}, amount: 1000, want: 0},
+		{name: "zero amount", fee: TransferFee{MaximumFee: 100, BasisPoints: 100}, amount: 0, want: 0},
+		{name: "exact fee", fee: TransferFee{MaximumFee: 100, BasisPoints: 100}, amount: 1000, want: 10},
+		{name: "fee rounded up", fee: TransferFee{MaximumFee: 100, BasisPoints: 100}, amount: 1001, want: 11},
+		{name: "fee capped by maximum", fee: TransferFee{MaximumFee: 5, BasisPoints: 100}, amount: 1000, want: 5},
+		{name: "fee of max amount", fee: TransferFee{MaximumFee: ^uint64(0), BasisPoints: maxBasisPoints}, amount: ^uint64(0), want: ^uint64(0)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.fee.Calculate(c.amount); got != c.want {
+				t.Fatalf("expected %d, got %d", c.want, got)
+			}
+		})
+	}
+}
+
+func TestParseExtensionKeys(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+
+	hook, err := ParseTransferHookProgram(append(make([]byte, 32), key...))
+	if err != nil || !bytes.Equal(hook.Bytes(), key) {
+		t.Fatalf("unexpected transfer hook program %s: %v", hook, err)
+	}
+
+	if _, err := ParseTransferHookProgram(key); err == nil {
+		t.Fatal("expected error for truncated transfer hook")
+	}
+
+	delegate, err := ParsePermanentDelegate(key)
+	if err != nil || !bytes.Equal(delegate.Bytes(), key) {
+		t.Fatalf("unexpected permanent delegate %s: %v", delegate, err)
+	}
+
+	if _, err := ParsePermanentDelegate(key[:31]); err == nil {
+		t.Fatal("expected error for truncated permanent delegate")
+	}
+}
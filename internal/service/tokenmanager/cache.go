@@ -0,0 +1,174 @@
+package tokenmanager
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	lru "github.com/hashicorp/golang-lru"
+	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	methodOnChainItem                = "OnChainItem"
+	methodItem                       = "Item"
+	methodOnChainItemByOther         = "OnChainItemByOther"
+	methodCollectionByCollectionData = "CollectionByCollectionData"
+	methodNativeCollectionData       = "NativeCollectionData"
+	methodCollectionData             = "CollectionData"
+	methodNetworkParams              = "NetworkParams"
+)
+
+// Cache is the bounded TTL cache over the token manager queries used during the deposits parsing.
+// Successful responses and NotFound errors are cached; other errors are never cached.
+// Network params are not changed by token manager events, so they are refreshed by TTL only.
+// Queries that are not cached are proxied to the core as is.
+type Cache struct {
+	tokentypes.QueryClient
+	ttl     time.Duration
+	entries *lru.Cache
+	// incremented on every purge to drop responses of the queries started before it
+	generation uint64
+}
+
+type entry struct {
+	value     proto.Message
+	err       error
+	expiresAt time.Time
+}
+
+func NewCache(rarimo *grpc.ClientConn, size int, ttl time.Duration) *Cache {
+	entries, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Cache{
+		QueryClient: tokentypes.NewQueryClient(rarimo),
+		ttl:         ttl,
+		entries:     entries,
+	}
+}
+
+// Purge removes all cached entries
+func (c *Cache) Purge() {
+	atomic.AddUint64(&c.generation, 1)
+	c.entries.Purge()
+}
+
+func (c *Cache) OnChainItem(ctx context.Context, in *tokentypes.QueryGetOnChainItemRequest, opts ...grpc.CallOption) (*tokentypes.QueryGetOnChainItemResponse, error) {
+	resp, err := c.get(methodOnChainItem, in, func() (proto.Message, error) {
+		return c.QueryClient.OnChainItem(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*tokentypes.QueryGetOnChainItemResponse), nil
+}
+
+func (c *Cache) Item(ctx context.Context, in *tokentypes.QueryGetItemRequest, opts ...grpc.CallOption) (*tokentypes.QueryGetItemResponse, error) {
+	resp, err := c.get(methodItem, in, func() (proto.Message, error) {
+		return c.QueryClient.Item(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*tokentypes.QueryGetItemResponse), nil
+}
+
+func (c *Cache) OnChainItemByOther(ctx context.Context, in *tokentypes.QueryGetOnChainItemByOtherRequest, opts ...grpc.CallOption) (*tokentypes.QueryGetOnChainItemByOtherResponse, error) {
+	resp, err := c.get(methodOnChainItemByOther, in, func() (proto.Message, error) {
+		return c.QueryClient.OnChainItemByOther(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*tokentypes.QueryGetOnChainItemByOtherResponse), nil
+}
+
+func (c *Cache) CollectionByCollectionData(ctx context.Context, in *tokentypes.QueryGetCollectionByCollectionDataRequest, opts ...grpc.CallOption) (*tokentypes.QueryGetCollectionByCollectionDataResponse, error) {
+	resp, err := c.get(methodCollectionByCollectionData, in, func() (proto.Message, error) {
+		return c.QueryClient.CollectionByCollectionData(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*tokentypes.QueryGetCollectionByCollectionDataResponse), nil
+}
+
+func (c *Cache) NativeCollectionData(ctx context.Context, in *tokentypes.QueryGetNativeCollectionDataRequest, opts ...grpc.CallOption) (*tokentypes.QueryGetNativeCollectionDataResponse, error) {
+	resp, err := c.get(methodNativeCollectionData, in, func() (proto.Message, error) {
+		return c.QueryClient.NativeCollectionData(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*tokentypes.QueryGetNativeCollectionDataResponse), nil
+}
+
+func (c *Cache) CollectionData(ctx context.Context, in *tokentypes.QueryGetCollectionDataRequest, opts ...grpc.CallOption) (*tokentypes.QueryGetCollectionDataResponse, error) {
+	resp, err := c.get(methodCollectionData, in, func() (proto.Message, error) {
+		return c.QueryClient.CollectionData(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*tokentypes.QueryGetCollectionDataResponse), nil
+}
+
+func (c *Cache) NetworkParams(ctx context.Context, in *tokentypes.QueryNetworkParamsRequest, opts ...grpc.CallOption) (*tokentypes.QueryNetworkParamsResponse, error) {
+	resp, err := c.get(methodNetworkParams, in, func() (proto.Message, error) {
+		return c.QueryClient.NetworkParams(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*tokentypes.QueryNetworkParamsResponse), nil
+}
+
+// get returns the copy of cached response or executes the query and caches its result.
+func (c *Cache) get(method string, req proto.Message, query func() (proto.Message, error)) (proto.Message, error) {
+	key := method + "/" + req.String()
+
+	if value, ok := c.entries.Get(key); ok {
+		e := value.(*entry)
+		if time.Now().Before(e.expiresAt) {
+			CacheRequestsMetric.WithLabelValues(method, CacheResultHit).Inc()
+			if e.err != nil {
+				return nil, e.err
+			}
+
+			return proto.Clone(e.value), nil
+		}
+	}
+
+	CacheRequestsMetric.WithLabelValues(method, CacheResultMiss).Inc()
+
+	generation := atomic.LoadUint64(&c.generation)
+	resp, err := query()
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	e := &entry{err: err, expiresAt: time.Now().Add(c.ttl)}
+	if err == nil {
+		e.value = proto.Clone(resp)
+	}
+
+	if atomic.LoadUint64(&c.generation) == generation {
+		c.entries.Add(key, e)
+	}
+
+	return resp, err
+}
@@ -0,0 +1,163 @@
+package tokenmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	libvoter "github.com/rarimo/saver-grpc-lib/voter"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+)
+
+const (
+	InvalidatorServiceName = "tokenmanager-cache-invalidator"
+	InvalidatorPoolSize    = 100
+)
+
+var (
+	// Proposals are executed in the gov EndBlocker, so their events are delivered with the new block.
+	blockQuery = "tm.event='NewBlock'"
+	// Only these token manager events can also be emitted from transactions.
+	txQueries = []string{
+		fmt.Sprintf("tm.event='Tx' AND %s.%s EXISTS", tokentypes.EventTypeItemCreated, tokentypes.AttributeKeyItemIndex),
+		fmt.Sprintf("tm.event='Tx' AND %s.%s EXISTS", tokentypes.EventTypeOnChainItemCreated, tokentypes.AttributeKeyOnChainItemChain),
+	}
+
+	cachedEventTypes = []string{
+		tokentypes.EventTypeCollectionCreated,
+		tokentypes.EventTypeCollectionRemoved,
+		tokentypes.EventTypeCollectionDataCreated,
+		tokentypes.EventTypeCollectionDataUpdated,
+		tokentypes.EventTypeCollectionDataRemoved,
+		tokentypes.EventTypeItemCreated,
+		tokentypes.EventTypeItemRemoved,
+		tokentypes.EventTypeOnChainItemCreated,
+		tokentypes.EventTypeOnChainItemRemoved,
+	}
+)
+
+// Invalidator subscribes to the token manager events on the tendermint core and purges the cache on every change.
+// Token manager entities are changed rarely, so the whole cache is dropped instead of tracking
+// the dependencies between the changed entities and cached queries.
+type Invalidator struct {
+	cache  *Cache
+	client rpcclient.EventsClient
+	log    *logan.Entry
+	cfg    libvoter.SubscriberConfig
+}
+
+func NewInvalidator(cache *Cache, client rpcclient.EventsClient, log *logan.Entry, cfg libvoter.SubscriberConfig) *Invalidator {
+	return &Invalidator{
+		cache:  cache,
+		client: client,
+		log:    log,
+		cfg:    cfg,
+	}
+}
+
+func (i *Invalidator) Run(ctx context.Context) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			i.log.WithRecover(rvr).Error("Invalidator panicked")
+		}
+	}()
+
+	running.WithBackOff(ctx,
+		i.log.WithField("who", "invalidator"),
+		"invalidator",
+		i.runOnce,
+		i.cfg.MinRetryPeriod, i.cfg.MinRetryPeriod, i.cfg.MaxRetryPeriod)
+}
+
+func (i *Invalidator) runOnce(ctx context.Context) error {
+	i.log.Infof("Starting subscription for the token manager changes")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	blocks, err := i.client.Subscribe(ctx, InvalidatorServiceName, blockQuery, InvalidatorPoolSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe to the new blocks")
+	}
+	defer i.unsubscribe(blockQuery)
+
+	txs := make(chan coretypes.ResultEvent, InvalidatorPoolSize)
+	for _, query := range txQueries {
+		out, err := i.client.Subscribe(ctx, InvalidatorServiceName, query, InvalidatorPoolSize)
+		if err != nil {
+			return errors.Wrap(err, "failed to subscribe to the token manager transactions", logan.F{"query": query})
+		}
+		defer i.unsubscribe(query)
+
+		go forward(ctx, out, txs)
+	}
+
+	// Changes could be missed while the subscription was not active
+	i.invalidate("subscription started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			i.log.Debug("context canceled, exiting")
+			return nil
+		case e, ok := <-blocks:
+			if !ok {
+				return errors.New("blocks subscription closed")
+			}
+
+			if hasTokenManagerEvents(e) {
+				i.invalidate("token manager proposal executed")
+			}
+		case <-txs:
+			i.invalidate("token manager transaction executed")
+		}
+	}
+}
+
+func (i *Invalidator) invalidate(reason string) {
+	i.log.WithField("reason", reason).Debug("Purging token manager cache")
+	i.cache.Purge()
+	CacheInvalidationsMetric.Inc()
+}
+
+func (i *Invalidator) unsubscribe(query string) {
+	if err := i.client.Unsubscribe(context.Background(), InvalidatorServiceName, query); err != nil {
+		i.log.WithError(err).WithField("query", query).Error("failed to unsubscribe")
+	}
+}
+
+func forward(ctx context.Context, from <-chan coretypes.ResultEvent, to chan<- coretypes.ResultEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-from:
+			if !ok {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case to <- e:
+			}
+		}
+	}
+}
+
+func hasTokenManagerEvents(e coretypes.ResultEvent) bool {
+	for key := range e.Events {
+		for _, eventType := range cachedEventTypes {
+			if strings.HasPrefix(key, eventType+".") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,21 @@
+package tokenmanager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	CacheResultHit  = "hit"
+	CacheResultMiss = "miss"
+)
+
+var (
+	CacheRequestsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tokenmanager_cache_requests_total",
+	}, []string{"method", "result"})
+
+	CacheInvalidationsMetric = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tokenmanager_cache_invalidations_total",
+	})
+)
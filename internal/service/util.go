@@ -6,20 +6,35 @@ import (
 	bin "github.com/gagliardetto/binary"
 	"github.com/olegfomenko/solana-go"
 	"github.com/olegfomenko/solana-go/rpc"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
-func GetInstructionAccounts(accounts []solana.PublicKey, indexes []uint16) []solana.PublicKey {
+// GetInstructionAccounts resolves the instruction account indexes to the transaction account keys.
+// Returns verifiers.ErrWrongOperationContent if any index is out of the transaction accounts range.
+func GetInstructionAccounts(accounts []solana.PublicKey, indexes []uint16) ([]solana.PublicKey, error) {
 	result := make([]solana.PublicKey, 0, len(indexes))
 	for _, i := range indexes {
+		if int(i) >= len(accounts) {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "invalid account index", logan.F{"index": i})
+		}
+
 		result = append(result, accounts[i])
 	}
-	return result
+	return result, nil
+}
+
+// Transaction is the decoded Solana transaction with its execution metadata.
+type Transaction struct {
+	*solana.Transaction
+	Meta *rpc.TransactionMeta
+	Slot uint64
 }
 
 // GetTransaction requests Solana transaction entry by signature.
 // Returns <nil> if tx was not successful.
-func GetTransaction(ctx context.Context, cli *rpc.Client, sig solana.Signature) (*solana.Transaction, error) {
+func GetTransaction(ctx context.Context, cli *rpc.Client, sig solana.Signature) (*Transaction, error) {
 	out, err := cli.GetTransaction(ctx, sig, &rpc.GetTransactionOpts{
 		Encoding: solana.EncodingBase64,
 	})
@@ -32,5 +47,9 @@ func GetTransaction(ctx context.Context, cli *rpc.Client, sig solana.Signature)
 	}
 
 	tx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(out.Transaction.GetBinary()))
-	return tx, errors.Wrap(err, "error decoding transaction")
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding transaction")
+	}
+
+	return &Transaction{Transaction: tx, Meta: out.Meta, Slot: out.Slot}, nil
 }
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func TestGetInstructionAccounts(t *testing.T) {
+	keys := []solana.PublicKey{solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()}
+
+	cases := []struct {
+		name    string
+		indexes []uint16
+		want    []solana.PublicKey
+		wantErr bool
+	}{
+		{name: "no accounts", indexes: nil, want: []solana.PublicKey{}},
+		{name: "in range", indexes: []uint16{1, 0, 1}, want: []solana.PublicKey{keys[1], keys[0], keys[1]}},
+		{name: "out of range", indexes: []uint16{0, 2}, wantErr: true},
+		{name: "max index", indexes: []uint16{65535}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			accounts, err := GetInstructionAccounts(keys, c.indexes)
+			if c.wantErr {
+				if errors.Cause(err) != verifiers.ErrWrongOperationContent {
+					t.Fatalf("expected wrong operation content, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(accounts) != len(c.want) {
+				t.Fatalf("expected %d accounts, got %d", len(c.want), len(accounts))
+			}
+
+			for i := range accounts {
+				if accounts[i] != c.want[i] {
+					t.Fatalf("account %d: expected %s, got %s", i, c.want[i], accounts[i])
+				}
+			}
+		})
+	}
+}
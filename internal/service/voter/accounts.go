@@ -0,0 +1,160 @@
+package voter
+
+import (
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/solana-program-go/contracts/bridge"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type depositAccounts struct {
+	owner       int
+	vault       int
+	mint        int
+	ownerAssoc  int
+	bridgeAssoc int
+}
+
+// tokenAccounts returns false if deposit does not use token accounts
+func (d depositAccounts) tokenAccounts() bool {
+	return d.mint >= 0
+}
+
+var depositAccountRoles = map[bridge.Instruction]depositAccounts{
+	bridge.InstructionDepositNative: {
+		owner:       bridge.DepositNativeOwnerIndex,
+		vault:       bridge.DepositNativeBridgeAdminIndex,
+		mint:        -1,
+		ownerAssoc:  -1,
+		bridgeAssoc: -1,
+	},
+	bridge.InstructionDepositFT: {
+		owner:       bridge.DepositFTOwnerIndex,
+		vault:       bridge.DepositFTBridgeAdminIndex,
+		mint:        bridge.DepositFTMintIndex,
+		ownerAssoc:  bridge.DepositFTOwnerAssocIndex,
+		bridgeAssoc: bridge.DepositFTBridgeAssocIndex,
+	},
+	bridge.InstructionDepositNFT: {
+		owner:       bridge.DepositNFTOwnerIndex,
+		vault:       bridge.DepositNFTBridgeAdminIndex,
+		mint:        bridge.DepositNFTMintIndex,
+		ownerAssoc:  bridge.DepositNFTOwnerAssocIndex,
+		bridgeAssoc: bridge.DepositNFTBridgeAssocIndex,
+	},
+}
+
+// VerifyAccounts checks the roles of the deposit instruction accounts:
+// the owner should sign the transaction, accounts whose balances are changed should be writable,
+// and token accounts should belong to the deposited mint and to the owner or the bridge respectively.
+// Returns verifiers.ErrWrongOperationContent in case of any violation.
+func VerifyAccounts(tx *service.Transaction, instruction solana.CompiledInstruction) error {
+	if len(instruction.Data) == 0 {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "empty instruction data")
+	}
+
+	roles, ok := depositAccountRoles[bridge.Instruction(instruction.Data[DataInstructionCodeIndex])]
+	if !ok {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "unknown deposit instruction")
+	}
+
+	key := func(index int) (solana.PublicKey, uint16, error) {
+		i, err := accountKey(tx, instruction, index)
+		if err != nil {
+			return solana.PublicKey{}, 0, err
+		}
+
+		return tx.Message.AccountKeys[i], i, nil
+	}
+
+	owner, _, err := key(roles.owner)
+	if err != nil {
+		return err
+	}
+
+	vault, _, err := key(roles.vault)
+	if err != nil {
+		return err
+	}
+
+	if !tx.Message.IsSigner(owner) {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "deposit owner has not signed the transaction", logan.F{"owner": owner.String()})
+	}
+
+	if !roles.tokenAccounts() {
+		return verifyWritable(tx, owner, vault)
+	}
+
+	mint, _, err := key(roles.mint)
+	if err != nil {
+		return err
+	}
+
+	ownerAssoc, ownerAssocIndex, err := key(roles.ownerAssoc)
+	if err != nil {
+		return err
+	}
+
+	bridgeAssoc, bridgeAssocIndex, err := key(roles.bridgeAssoc)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyWritable(tx, ownerAssoc, bridgeAssoc); err != nil {
+		return err
+	}
+
+	if tx.Meta == nil {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "transaction metadata is not available")
+	}
+
+	// Owner token account should exist and hold the tokens before the deposit
+	if err := verifyTokenAccount(tx.Meta.PreTokenBalances, ownerAssocIndex, mint, owner); err != nil {
+		return errors.Wrap(err, "invalid owner token account", logan.F{"account": ownerAssoc.String()})
+	}
+
+	// Bridge token account can be created during the deposit
+	if err := verifyTokenAccount(tx.Meta.PostTokenBalances, bridgeAssocIndex, mint, vault); err != nil {
+		return errors.Wrap(err, "invalid bridge token account", logan.F{"account": bridgeAssoc.String()})
+	}
+
+	return nil
+}
+
+func verifyWritable(tx *service.Transaction, accounts ...solana.PublicKey) error {
+	for _, account := range accounts {
+		if !tx.Message.IsWritable(account) {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "deposit account is not writable", logan.F{"account": account.String()})
+		}
+	}
+
+	return nil
+}
+
+func verifyTokenAccount(balances []rpc.TokenBalance, account uint16, mint, owner solana.PublicKey) error {
+	for _, balance := range balances {
+		if balance.AccountIndex != account {
+			continue
+		}
+
+		if balance.Mint != mint {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "token account mint mismatch", logan.F{
+				"expected": mint.String(),
+				"got":      balance.Mint.String(),
+			})
+		}
+
+		if balance.Owner == nil || *balance.Owner != owner {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "token account owner mismatch", logan.F{
+				"expected": owner.String(),
+			})
+		}
+
+		return nil
+	}
+
+	return errors.Wrap(verifiers.ErrWrongOperationContent, "token account data is not available")
+}
@@ -0,0 +1,154 @@
+package voter
+
+import (
+	"testing"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/solana-program-go/contracts/bridge"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func TestVerifyAccounts(t *testing.T) {
+	// Signer and writable accounts go first, read-only accounts go last
+	const (
+		owner = iota
+		vault
+		ownerAssoc
+		bridgeAssoc
+		mint
+		program
+		accountsCount
+	)
+
+	keys := make([]solana.PublicKey, accountsCount)
+	for i := range keys {
+		keys[i] = solana.NewWallet().PublicKey()
+	}
+
+	header := solana.MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 2}
+	otherMint := solana.NewWallet().PublicKey()
+
+	native := solana.CompiledInstruction{Accounts: []uint16{vault, owner}, Data: []byte{byte(bridge.InstructionDepositNative)}}
+	ft := func(accounts ...uint16) solana.CompiledInstruction {
+		return solana.CompiledInstruction{Accounts: accounts, Data: []byte{byte(bridge.InstructionDepositFT)}}
+	}
+	nft := func(accounts ...uint16) solana.CompiledInstruction {
+		return solana.CompiledInstruction{Accounts: accounts, Data: []byte{byte(bridge.InstructionDepositNFT)}}
+	}
+
+	balance := func(account uint16, mint solana.PublicKey, owner *solana.PublicKey) rpc.TokenBalance {
+		return rpc.TokenBalance{AccountIndex: account, Mint: mint, Owner: owner, UiTokenAmount: &rpc.UiTokenAmount{Amount: "1"}}
+	}
+
+	validMeta := &rpc.TransactionMeta{
+		PreTokenBalances:  []rpc.TokenBalance{balance(ownerAssoc, keys[mint], &keys[owner])},
+		PostTokenBalances: []rpc.TokenBalance{balance(ownerAssoc, keys[mint], &keys[owner]), balance(bridgeAssoc, keys[mint], &keys[vault])},
+	}
+
+	cases := []struct {
+		name        string
+		header      *solana.MessageHeader
+		instruction solana.CompiledInstruction
+		meta        *rpc.TransactionMeta
+		wantErr     bool
+	}{
+		{name: "native deposit", instruction: native},
+		{name: "FT deposit", instruction: ft(vault, mint, ownerAssoc, bridgeAssoc, owner, program), meta: validMeta},
+		{name: "NFT deposit", instruction: nft(vault, mint, ownerAssoc, bridgeAssoc, owner), meta: validMeta},
+		{
+			name:        "bridge token account created before deposit",
+			instruction: ft(vault, mint, ownerAssoc, bridgeAssoc, owner, program),
+			meta: &rpc.TransactionMeta{
+				PreTokenBalances:  []rpc.TokenBalance{balance(ownerAssoc, keys[mint], &keys[owner]), balance(bridgeAssoc, keys[mint], &keys[vault])},
+				PostTokenBalances: []rpc.TokenBalance{balance(bridgeAssoc, keys[mint], &keys[vault])},
+			},
+		},
+		{name: "empty instruction data", instruction: solana.CompiledInstruction{Accounts: native.Accounts}, wantErr: true},
+		{name: "unknown instruction", instruction: solana.CompiledInstruction{Accounts: native.Accounts, Data: []byte{255}}, wantErr: true},
+		{name: "owner has not signed", instruction: solana.CompiledInstruction{Accounts: []uint16{vault, ownerAssoc}, Data: native.Data}, wantErr: true},
+		{name: "owner is read-only signer", header: &solana.MessageHeader{NumRequiredSignatures: 1, NumReadonlySignedAccounts: 1, NumReadonlyUnsignedAccounts: 2}, instruction: native, wantErr: true},
+		{name: "native vault is read-only", instruction: solana.CompiledInstruction{Accounts: []uint16{program, owner}, Data: native.Data}, wantErr: true},
+		{name: "owner token account is read-only", instruction: ft(vault, mint, program, bridgeAssoc, owner, program), meta: validMeta, wantErr: true},
+		{name: "bridge token account is read-only", instruction: ft(vault, mint, ownerAssoc, mint, owner, program), meta: validMeta, wantErr: true},
+		{name: "missing instruction account", instruction: ft(vault, mint, ownerAssoc, bridgeAssoc), meta: validMeta, wantErr: true},
+		{name: "account index out of range", instruction: ft(vault, accountsCount, ownerAssoc, bridgeAssoc, owner, program), meta: validMeta, wantErr: true},
+		{name: "metadata is not available", instruction: ft(vault, mint, ownerAssoc, bridgeAssoc, owner, program), wantErr: true},
+		{
+			name:        "owner token account of another mint",
+			instruction: ft(vault, mint, ownerAssoc, bridgeAssoc, owner, program),
+			meta: &rpc.TransactionMeta{
+				PreTokenBalances:  []rpc.TokenBalance{balance(ownerAssoc, otherMint, &keys[owner])},
+				PostTokenBalances: validMeta.PostTokenBalances,
+			},
+			wantErr: true,
+		},
+		{
+			name:        "owner token account of another owner",
+			instruction: ft(vault, mint, ownerAssoc, bridgeAssoc, owner, program),
+			meta: &rpc.TransactionMeta{
+				PreTokenBalances:  []rpc.TokenBalance{balance(ownerAssoc, keys[mint], &keys[vault])},
+				PostTokenBalances: validMeta.PostTokenBalances,
+			},
+			wantErr: true,
+		},
+		{
+			name:        "owner token account without owner",
+			instruction: ft(vault, mint, ownerAssoc, bridgeAssoc, owner, program),
+			meta: &rpc.TransactionMeta{
+				PreTokenBalances:  []rpc.TokenBalance{balance(ownerAssoc, keys[mint], nil)},
+				PostTokenBalances: validMeta.PostTokenBalances,
+			},
+			wantErr: true,
+		},
+		{
+			name:        "owner token account did not exist",
+			instruction: ft(vault, mint, ownerAssoc, bridgeAssoc, owner, program),
+			meta:        &rpc.TransactionMeta{PostTokenBalances: validMeta.PostTokenBalances},
+			wantErr:     true,
+		},
+		{
+			name:        "bridge token account of another mint",
+			instruction: nft(vault, mint, ownerAssoc, bridgeAssoc, owner),
+			meta: &rpc.TransactionMeta{
+				PreTokenBalances:  validMeta.PreTokenBalances,
+				PostTokenBalances: []rpc.TokenBalance{balance(bridgeAssoc, otherMint, &keys[vault])},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "bridge token account not owned by vault",
+			instruction: nft(vault, mint, ownerAssoc, bridgeAssoc, owner),
+			meta: &rpc.TransactionMeta{
+				PreTokenBalances:  validMeta.PreTokenBalances,
+				PostTokenBalances: []rpc.TokenBalance{balance(bridgeAssoc, keys[mint], &keys[owner])},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			message := solana.Message{Header: header, AccountKeys: keys}
+			if c.header != nil {
+				message.Header = *c.header
+			}
+
+			tx := &service.Transaction{Transaction: &solana.Transaction{Message: message}, Meta: c.meta}
+
+			err := VerifyAccounts(tx, c.instruction)
+			if !c.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if errors.Cause(err) != verifiers.ErrWrongOperationContent {
+				t.Fatalf("expected wrong operation content, got %v", err)
+			}
+		})
+	}
+}
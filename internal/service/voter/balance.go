@@ -0,0 +1,231 @@
+package voter
+
+import (
+	"math/big"
+
+	"github.com/near/borsh-go"
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/solana-program-go/contracts/bridge"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type tokenDeposit struct {
+	mint   solana.PublicKey
+	amount *big.Int
+	// Token-2022 deposits may be charged with transfer fee that is verified by the FT operator
+	token2022 bool
+	count     int
+}
+
+// VerifyBalances checks that all bridge deposits in the transaction are backed by the actual balance changes:
+// the bridge vault should receive exactly the deposited amount of lamports (native deposits)
+// or tokens of the deposited mint (FT and NFT deposits).
+// Token-2022 FT deposits should be the only deposit to the vault in the transaction,
+// and the vault should receive the positive amount not greater than the deposited one.
+// Returns verifiers.ErrWrongOperationContent in case of any mismatch.
+func VerifyBalances(program solana.PublicKey, tx *service.Transaction) error {
+	if tx.Meta == nil {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "transaction metadata is not available")
+	}
+
+	lamports := make(map[uint16]*big.Int)
+	tokens := make(map[uint16]*tokenDeposit)
+
+	for _, instruction := range tx.Message.Instructions {
+		if int(instruction.ProgramIDIndex) >= len(tx.Message.AccountKeys) || tx.Message.AccountKeys[instruction.ProgramIDIndex] != program || len(instruction.Data) == 0 {
+			continue
+		}
+
+		switch bridge.Instruction(instruction.Data[DataInstructionCodeIndex]) {
+		case bridge.InstructionDepositNative:
+			var args bridge.DepositNativeArgs
+			if err := borsh.Deserialize(&args, instruction.Data); err != nil {
+				return errors.Wrap(verifiers.ErrWrongOperationContent, "error desser tx args: "+err.Error())
+			}
+
+			vault, err := accountKey(tx, instruction, bridge.DepositNativeBridgeAdminIndex)
+			if err != nil {
+				return err
+			}
+
+			addLamports(lamports, vault, args.Amount)
+		case bridge.InstructionDepositFT:
+			var args bridge.DepositFTArgs
+			if err := borsh.Deserialize(&args, instruction.Data); err != nil {
+				return errors.Wrap(verifiers.ErrWrongOperationContent, "error desser tx args: "+err.Error())
+			}
+
+			if err := addTokens(tokens, tx, instruction, bridge.DepositFTBridgeAssocIndex, bridge.DepositFTMintIndex, args.Amount); err != nil {
+				return err
+			}
+
+			program, err := accountKey(tx, instruction, DepositFTTokenProgramIndex)
+			if err != nil {
+				return err
+			}
+
+			if tx.Message.AccountKeys[program] == service.Token2022ProgramID {
+				vault, _ := accountIndex(instruction, bridge.DepositFTBridgeAssocIndex)
+				tokens[vault].token2022 = true
+			}
+		case bridge.InstructionDepositNFT:
+			if err := addTokens(tokens, tx, instruction, bridge.DepositNFTBridgeAssocIndex, bridge.DepositNFTMintIndex, 1); err != nil {
+				return err
+			}
+		}
+	}
+
+	for vault, amount := range lamports {
+		if int(vault) >= len(tx.Meta.PreBalances) || int(vault) >= len(tx.Meta.PostBalances) {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "vault balance is not available")
+		}
+
+		received := new(big.Int).Sub(
+			new(big.Int).SetUint64(tx.Meta.PostBalances[vault]),
+			new(big.Int).SetUint64(tx.Meta.PreBalances[vault]),
+		)
+
+		if received.Cmp(amount) != 0 {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "vault lamports balance change mismatch", logan.F{
+				"vault":    tx.Message.AccountKeys[vault].String(),
+				"expected": amount.String(),
+				"received": received.String(),
+			})
+		}
+	}
+
+	for vault, deposit := range tokens {
+		pre, err := tokenBalance(tx.Meta.PreTokenBalances, vault, deposit.mint)
+		if err != nil {
+			return err
+		}
+
+		post, err := tokenBalance(tx.Meta.PostTokenBalances, vault, deposit.mint)
+		if err != nil {
+			return err
+		}
+
+		received := new(big.Int).Sub(post, pre)
+
+		if deposit.token2022 {
+			if deposit.count > 1 {
+				return errors.Wrap(verifiers.ErrWrongOperationContent, "several Token-2022 deposits to the same vault are not supported", logan.F{
+					"vault": tx.Message.AccountKeys[vault].String(),
+				})
+			}
+
+			if received.Sign() <= 0 || received.Cmp(deposit.amount) > 0 {
+				return errors.Wrap(verifiers.ErrWrongOperationContent, "vault token balance change mismatch", logan.F{
+					"vault":    tx.Message.AccountKeys[vault].String(),
+					"mint":     deposit.mint.String(),
+					"expected": deposit.amount.String(),
+					"received": received.String(),
+				})
+			}
+
+			continue
+		}
+
+		if received.Cmp(deposit.amount) != 0 {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "vault token balance change mismatch", logan.F{
+				"vault":    tx.Message.AccountKeys[vault].String(),
+				"mint":     deposit.mint.String(),
+				"expected": deposit.amount.String(),
+				"received": received.String(),
+			})
+		}
+	}
+
+	return nil
+}
+
+func accountIndex(instruction solana.CompiledInstruction, index int) (uint16, error) {
+	if index >= len(instruction.Accounts) {
+		return 0, errors.Wrap(verifiers.ErrWrongOperationContent, "instruction account is missing", logan.F{"index": index})
+	}
+
+	return instruction.Accounts[index], nil
+}
+
+// accountKey returns the transaction account index of the instruction account
+// checking that it refers to the existing transaction account.
+func accountKey(tx *service.Transaction, instruction solana.CompiledInstruction, index int) (uint16, error) {
+	i, err := accountIndex(instruction, index)
+	if err != nil {
+		return 0, err
+	}
+
+	if int(i) >= len(tx.Message.AccountKeys) {
+		return 0, errors.Wrap(verifiers.ErrWrongOperationContent, "invalid account index", logan.F{"index": i})
+	}
+
+	return i, nil
+}
+
+func addLamports(lamports map[uint16]*big.Int, vault uint16, amount uint64) {
+	if _, ok := lamports[vault]; !ok {
+		lamports[vault] = new(big.Int)
+	}
+
+	lamports[vault].Add(lamports[vault], new(big.Int).SetUint64(amount))
+}
+
+func addTokens(tokens map[uint16]*tokenDeposit, tx *service.Transaction, instruction solana.CompiledInstruction, vaultIndex, mintIndex int, amount uint64) error {
+	vault, err := accountKey(tx, instruction, vaultIndex)
+	if err != nil {
+		return err
+	}
+
+	mint, err := accountKey(tx, instruction, mintIndex)
+	if err != nil {
+		return err
+	}
+
+	deposit, ok := tokens[vault]
+	if !ok {
+		deposit = &tokenDeposit{mint: tx.Message.AccountKeys[mint], amount: new(big.Int)}
+		tokens[vault] = deposit
+	}
+
+	if deposit.mint != tx.Message.AccountKeys[mint] {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "vault is used for several mints")
+	}
+
+	deposit.amount.Add(deposit.amount, new(big.Int).SetUint64(amount))
+	deposit.count++
+	return nil
+}
+
+// tokenBalance returns the raw token amount of the account or zero if account has no balance entry
+// (for example, when the account is created in the same transaction).
+func tokenBalance(balances []rpc.TokenBalance, account uint16, mint solana.PublicKey) (*big.Int, error) {
+	for _, balance := range balances {
+		if balance.AccountIndex != account {
+			continue
+		}
+
+		if balance.Mint != mint {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "vault holds another mint", logan.F{
+				"expected": mint.String(),
+				"got":      balance.Mint.String(),
+			})
+		}
+
+		if balance.UiTokenAmount == nil {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "token amount is not available")
+		}
+
+		amount, ok := new(big.Int).SetString(balance.UiTokenAmount.Amount, 10)
+		if !ok {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "invalid token amount", logan.F{"amount": balance.UiTokenAmount.Amount})
+		}
+
+		return amount, nil
+	}
+
+	return new(big.Int), nil
+}
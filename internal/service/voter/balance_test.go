@@ -0,0 +1,299 @@
+package voter
+
+import (
+	"testing"
+
+	"github.com/near/borsh-go"
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/solana-program-go/contracts/bridge"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Transaction account indexes used by the balance test deposits
+const (
+	testProgram = iota
+	testNativeVault
+	testOwner
+	testMint
+	testVault
+	testOwnerAssoc
+	testTokenProgram
+	testToken2022Program
+	testOtherMint
+	testOtherVault
+	testAccountsCount
+)
+
+func testAccountKeys() []solana.PublicKey {
+	keys := make([]solana.PublicKey, testAccountsCount)
+	for i := range keys {
+		keys[i] = solana.NewWallet().PublicKey()
+	}
+
+	keys[testTokenProgram] = solana.TokenProgramID
+	keys[testToken2022Program] = service.Token2022ProgramID
+	return keys
+}
+
+func mustBorsh(t *testing.T, args interface{}) []byte {
+	data, err := borsh.Serialize(args)
+	if err != nil {
+		t.Fatalf("failed to serialize args: %v", err)
+	}
+	return data
+}
+
+func nativeDeposit(t *testing.T, amount uint64) solana.CompiledInstruction {
+	return solana.CompiledInstruction{
+		ProgramIDIndex: testProgram,
+		Accounts:       []uint16{testNativeVault, testOwner},
+		Data:           mustBorsh(t, bridge.DepositNativeArgs{Instruction: bridge.InstructionDepositNative, Amount: amount}),
+	}
+}
+
+func ftDeposit(t *testing.T, mint, vault, program uint16, amount uint64) solana.CompiledInstruction {
+	return solana.CompiledInstruction{
+		ProgramIDIndex: testProgram,
+		Accounts:       []uint16{testNativeVault, mint, testOwnerAssoc, vault, testOwner, program},
+		Data:           mustBorsh(t, bridge.DepositFTArgs{Instruction: bridge.InstructionDepositFT, Amount: amount}),
+	}
+}
+
+func nftDeposit(t *testing.T, mint, vault uint16) solana.CompiledInstruction {
+	return solana.CompiledInstruction{
+		ProgramIDIndex: testProgram,
+		Accounts:       []uint16{testNativeVault, mint, testOwnerAssoc, vault, testOwner},
+		Data:           mustBorsh(t, bridge.DepositNFTArgs{Instruction: bridge.InstructionDepositNFT}),
+	}
+}
+
+func tokenAmount(account uint16, mint solana.PublicKey, amount string) rpc.TokenBalance {
+	return rpc.TokenBalance{AccountIndex: account, Mint: mint, UiTokenAmount: &rpc.UiTokenAmount{Amount: amount}}
+}
+
+func TestVerifyBalances(t *testing.T) {
+	keys := testAccountKeys()
+	mint, otherMint := keys[testMint], keys[testOtherMint]
+
+	lamports := func(pre, post uint64) *rpc.TransactionMeta {
+		return &rpc.TransactionMeta{
+			PreBalances:  []uint64{0, pre, 0},
+			PostBalances: []uint64{0, post, 0},
+		}
+	}
+
+	tokens := func(pre, post []rpc.TokenBalance) *rpc.TransactionMeta {
+		return &rpc.TransactionMeta{PreTokenBalances: pre, PostTokenBalances: post}
+	}
+
+	cases := []struct {
+		name         string
+		instructions []solana.CompiledInstruction
+		meta         *rpc.TransactionMeta
+		wantErr      bool
+	}{
+		{
+			name:         "native deposit",
+			instructions: []solana.CompiledInstruction{nativeDeposit(t, 50)},
+			meta:         lamports(100, 150),
+		},
+		{
+			name:         "native deposit mismatch",
+			instructions: []solana.CompiledInstruction{nativeDeposit(t, 50)},
+			meta:         lamports(100, 149),
+			wantErr:      true,
+		},
+		{
+			name:         "native deposits aggregated",
+			instructions: []solana.CompiledInstruction{nativeDeposit(t, 30), nativeDeposit(t, 20)},
+			meta:         lamports(100, 150),
+		},
+		{
+			name:         "native deposits aggregated mismatch",
+			instructions: []solana.CompiledInstruction{nativeDeposit(t, 30), nativeDeposit(t, 20)},
+			meta:         lamports(100, 130),
+			wantErr:      true,
+		},
+		{
+			name:         "native vault balance missing",
+			instructions: []solana.CompiledInstruction{nativeDeposit(t, 50)},
+			meta:         &rpc.TransactionMeta{},
+			wantErr:      true,
+		},
+		{
+			name:         "SPL deposit to new vault",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testTokenProgram, 100)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, mint, "100")}),
+		},
+		{
+			name:         "SPL deposit does not allow fees",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testTokenProgram, 100)},
+			meta:         tokens([]rpc.TokenBalance{tokenAmount(testVault, mint, "10")}, []rpc.TokenBalance{tokenAmount(testVault, mint, "109")}),
+			wantErr:      true,
+		},
+		{
+			name: "SPL deposits aggregated",
+			instructions: []solana.CompiledInstruction{
+				ftDeposit(t, testMint, testVault, testTokenProgram, 100),
+				ftDeposit(t, testMint, testVault, testTokenProgram, 50),
+			},
+			meta: tokens([]rpc.TokenBalance{tokenAmount(testVault, mint, "10")}, []rpc.TokenBalance{tokenAmount(testVault, mint, "160")}),
+		},
+		{
+			name: "SPL deposits aggregated mismatch",
+			instructions: []solana.CompiledInstruction{
+				ftDeposit(t, testMint, testVault, testTokenProgram, 100),
+				ftDeposit(t, testMint, testVault, testTokenProgram, 50),
+			},
+			meta:    tokens([]rpc.TokenBalance{tokenAmount(testVault, mint, "10")}, []rpc.TokenBalance{tokenAmount(testVault, mint, "110")}),
+			wantErr: true,
+		},
+		{
+			name: "deposits to several vaults",
+			instructions: []solana.CompiledInstruction{
+				nativeDeposit(t, 5),
+				ftDeposit(t, testMint, testVault, testTokenProgram, 100),
+				nftDeposit(t, testOtherMint, testOtherVault),
+			},
+			meta: &rpc.TransactionMeta{
+				PreBalances:       []uint64{0, 0, 0},
+				PostBalances:      []uint64{0, 5, 0},
+				PostTokenBalances: []rpc.TokenBalance{tokenAmount(testVault, mint, "100"), tokenAmount(testOtherVault, otherMint, "1")},
+			},
+		},
+		{
+			name:         "vault used for several mints",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testTokenProgram, 100), nftDeposit(t, testOtherMint, testVault)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, mint, "101")}),
+			wantErr:      true,
+		},
+		{
+			name:         "vault holds another mint",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testTokenProgram, 100)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, otherMint, "100")}),
+			wantErr:      true,
+		},
+		{
+			name:         "invalid token amount",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testTokenProgram, 100)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, mint, "1e2")}),
+			wantErr:      true,
+		},
+		{
+			name:         "Token-2022 deposit with transfer fee",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testToken2022Program, 100)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, mint, "98")}),
+		},
+		{
+			name:         "Token-2022 deposit without transfer fee",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testToken2022Program, 100)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, mint, "100")}),
+		},
+		{
+			name:         "Token-2022 deposit received more than deposited",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testToken2022Program, 100)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, mint, "101")}),
+			wantErr:      true,
+		},
+		{
+			name:         "Token-2022 deposit received nothing",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testToken2022Program, 100)},
+			meta:         tokens([]rpc.TokenBalance{tokenAmount(testVault, mint, "10")}, []rpc.TokenBalance{tokenAmount(testVault, mint, "10")}),
+			wantErr:      true,
+		},
+		{
+			name: "several Token-2022 deposits to the same vault",
+			instructions: []solana.CompiledInstruction{
+				ftDeposit(t, testMint, testVault, testToken2022Program, 100),
+				ftDeposit(t, testMint, testVault, testToken2022Program, 100),
+			},
+			meta:    tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, mint, "196")}),
+			wantErr: true,
+		},
+		{
+			name:         "NFT deposit",
+			instructions: []solana.CompiledInstruction{nftDeposit(t, testOtherMint, testOtherVault)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testOtherVault, otherMint, "1")}),
+		},
+		{
+			name:         "NFT not received",
+			instructions: []solana.CompiledInstruction{nftDeposit(t, testOtherMint, testOtherVault)},
+			meta:         tokens(nil, nil),
+			wantErr:      true,
+		},
+		{
+			name: "instructions of other programs are ignored",
+			instructions: []solana.CompiledInstruction{
+				{ProgramIDIndex: testTokenProgram, Accounts: []uint16{testVault}, Data: []byte{byte(bridge.InstructionDepositNative)}},
+				{ProgramIDIndex: testAccountsCount, Data: []byte{byte(bridge.InstructionDepositNative)}},
+				{ProgramIDIndex: testProgram},
+			},
+			meta: tokens(nil, nil),
+		},
+		{
+			name:         "missing instruction account",
+			instructions: []solana.CompiledInstruction{{ProgramIDIndex: testProgram, Accounts: []uint16{testNativeVault}, Data: ftDeposit(t, testMint, testVault, testTokenProgram, 1).Data}},
+			meta:         tokens(nil, nil),
+			wantErr:      true,
+		},
+		{
+			name:         "vault index out of range",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testAccountsCount, testTokenProgram, 100)},
+			meta:         tokens(nil, nil),
+			wantErr:      true,
+		},
+		{
+			name:         "mint index out of range",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, 65535, testVault, testTokenProgram, 100)},
+			meta:         tokens(nil, nil),
+			wantErr:      true,
+		},
+		{
+			name:         "token program index out of range",
+			instructions: []solana.CompiledInstruction{ftDeposit(t, testMint, testVault, testAccountsCount, 100)},
+			meta:         tokens(nil, []rpc.TokenBalance{tokenAmount(testVault, mint, "100")}),
+			wantErr:      true,
+		},
+		{
+			name:         "native vault index out of range",
+			instructions: []solana.CompiledInstruction{{ProgramIDIndex: testProgram, Accounts: []uint16{testAccountsCount}, Data: nativeDeposit(t, 1).Data}},
+			meta:         lamports(0, 1),
+			wantErr:      true,
+		},
+		{
+			name:         "malformed instruction data",
+			instructions: []solana.CompiledInstruction{{ProgramIDIndex: testProgram, Accounts: []uint16{testNativeVault}, Data: []byte{byte(bridge.InstructionDepositNative), 1}}},
+			meta:         lamports(0, 1),
+			wantErr:      true,
+		},
+		{
+			name:         "metadata is not available",
+			instructions: []solana.CompiledInstruction{nativeDeposit(t, 1)},
+			wantErr:      true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tx := &service.Transaction{
+				Transaction: &solana.Transaction{Message: solana.Message{AccountKeys: keys, Instructions: c.instructions}},
+				Meta:        c.meta,
+			}
+
+			err := VerifyBalances(keys[testProgram], tx)
+			if !c.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if errors.Cause(err) != verifiers.ErrWrongOperationContent {
+				t.Fatalf("expected wrong operation content, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package voter
+
+import (
+	goerr "errors"
+
+	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"github.com/rarimo/sol-saver-svc/internal/config"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// ErrInvalidBundle is returned for deposits with the bundle that can not be executed on the target network.
+// Such deposits should be refunded instead of creating the transfer operation.
+var ErrInvalidBundle = goerr.New("invalid bundle")
+
+// BundleVerifier decodes and validates the deposit bundles.
+type BundleVerifier struct {
+	log  *logan.Entry
+	conf config.BundleConf
+}
+
+func NewBundleVerifier(cfg config.Config) *BundleVerifier {
+	return &BundleVerifier{
+		log:  cfg.Log(),
+		conf: cfg.BundleConf(),
+	}
+}
+
+// Verify checks the deposit bundle against the configured limits. Bundles targeting EVM networks are decoded
+// as the EVM bridge bundler calls and validated, bundles for other network types are passed to core as is.
+// Returns false if deposit has no bundle data and ErrInvalidBundle if the bundle is malformed.
+func (b *BundleVerifier) Verify(network *tokentypes.Network, data *[]byte, salt *[32]byte) (bool, error) {
+	if data == nil || len(*data) == 0 {
+		return false, nil
+	}
+
+	if salt == nil {
+		return false, errors.Wrap(ErrInvalidBundle, "bundle salt is missing")
+	}
+
+	if len(*data) > b.conf.MaxSize {
+		return false, errors.Wrap(ErrInvalidBundle, "bundle size exceeds the limit", logan.F{
+			"size":  len(*data),
+			"limit": b.conf.MaxSize,
+		})
+	}
+
+	if network.Type != tokentypes.NetworkType_EVM {
+		b.log.WithFields(logan.F{"network": network.Name, "network_type": network.Type.String()}).Debug("deposit bundle is not decoded for non-EVM network")
+		return true, nil
+	}
+
+	bundle, err := service.DecodeBundle(*data)
+	if err != nil {
+		return false, errors.Wrap(ErrInvalidBundle, err.Error())
+	}
+
+	if len(bundle.Calls) == 0 || len(bundle.Calls) > b.conf.MaxCalls {
+		return false, errors.Wrap(ErrInvalidBundle, "bundle calls amount is out of range", logan.F{
+			"calls": len(bundle.Calls),
+			"limit": b.conf.MaxCalls,
+		})
+	}
+
+	b.log.WithFields(logan.F{"network": network.Name, "calls": len(bundle.Calls)}).Debug("deposit bundle decoded: " + bundle.String())
+	return true, nil
+}
@@ -0,0 +1,99 @@
+package voter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"github.com/rarimo/sol-saver-svc/internal/config"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func encodeBundle(t *testing.T, calls int) []byte {
+	var arguments abi.Arguments
+	for _, typ := range []string{"address[]", "uint256[]", "bytes[]"} {
+		argType, err := abi.NewType(typ, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		arguments = append(arguments, abi.Argument{Type: argType})
+	}
+
+	targets := make([]common.Address, calls)
+	values := make([]*big.Int, calls)
+	data := make([][]byte, calls)
+	for i := range targets {
+		targets[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+		values[i] = big.NewInt(0)
+		data[i] = []byte{1, 2, 3}
+	}
+
+	encoded, err := arguments.Pack(targets, values, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func TestBundleVerify(t *testing.T) {
+	verifier := &BundleVerifier{
+		log:  logan.New(),
+		conf: config.BundleConf{MaxSize: 1024, MaxCalls: 2},
+	}
+
+	evm := &tokentypes.Network{Name: "Ethereum", Type: tokentypes.NetworkType_EVM}
+	near := &tokentypes.Network{Name: "Near", Type: tokentypes.NetworkType_Near}
+
+	salt := &[32]byte{1}
+	valid := encodeBundle(t, 2)
+	tooManyCalls := encodeBundle(t, 3)
+	noCalls := encodeBundle(t, 0)
+	garbage := []byte{1, 2, 3}
+	trailing := append(encodeBundle(t, 1), 0)
+	large := make([]byte, 1025)
+
+	cases := []struct {
+		name    string
+		network *tokentypes.Network
+		data    *[]byte
+		salt    *[32]byte
+		want    bool
+		wantErr bool
+	}{
+		{name: "no bundle", network: evm},
+		{name: "empty bundle", network: evm, data: &[]byte{}},
+		{name: "valid EVM bundle", network: evm, data: &valid, salt: salt, want: true},
+		{name: "missing salt", network: evm, data: &valid, wantErr: true},
+		{name: "size exceeds limit", network: evm, data: &large, salt: salt, wantErr: true},
+		{name: "malformed EVM bundle", network: evm, data: &garbage, salt: salt, wantErr: true},
+		{name: "non-canonical EVM bundle", network: evm, data: &trailing, salt: salt, wantErr: true},
+		{name: "EVM bundle without calls", network: evm, data: &noCalls, salt: salt, wantErr: true},
+		{name: "EVM bundle with too many calls", network: evm, data: &tooManyCalls, salt: salt, wantErr: true},
+		{name: "non-EVM bundle is passed as is", network: near, data: &garbage, salt: salt, want: true},
+		{name: "non-EVM bundle without salt", network: near, data: &garbage, wantErr: true},
+		{name: "non-EVM bundle exceeding size limit", network: near, data: &large, salt: salt, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hasBundle, err := verifier.Verify(c.network, c.data, c.salt)
+			if c.wantErr {
+				if errors.Cause(err) != ErrInvalidBundle {
+					t.Fatalf("expected invalid bundle, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if hasBundle != c.want {
+				t.Fatalf("expected %v, got %v", c.want, hasBundle)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package voter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	libvoter "github.com/rarimo/saver-grpc-lib/voter"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc"
+)
+
+// Catchupper catches up old unsigned operations from core.
+// Operations are processed concurrently by the limited amount of workers.
+type Catchupper struct {
+	rarimoClient rarimotypes.QueryClient
+	voter        *libvoter.Voter
+	log          *logan.Entry
+	workers      chan struct{}
+}
+
+// NewCatchupper creates the catchup instance for adding all unsigned operations to the pool
+func NewCatchupper(rarimo *grpc.ClientConn, voter *libvoter.Voter, log *logan.Entry, workers int) *Catchupper {
+	return &Catchupper{
+		rarimoClient: rarimotypes.NewQueryClient(rarimo),
+		voter:        voter,
+		log:          log,
+		workers:      make(chan struct{}, workers),
+	}
+}
+
+// Run processes all unvoted operations and waits for their processing to be finished.
+func (c *Catchupper) Run(ctx context.Context) {
+	c.log.Infof("Starting catchup unvoted operations")
+
+	var wg sync.WaitGroup
+	var nextKey []byte
+
+	for {
+		operations, err := c.rarimoClient.OperationAll(ctx, &rarimotypes.QueryAllOperationRequest{
+			Pagination: &query.PageRequest{
+				Key: nextKey,
+			},
+		})
+		if err != nil {
+			panic(errors.Wrap(err, "failed to get operations"))
+		}
+
+		for _, op := range operations.Operation {
+			if op.Status != rarimotypes.OpStatus_INITIALIZED {
+				continue
+			}
+
+			c.log.WithField("index", op.Index).Info("New unapproved operation found")
+
+			_, err := c.rarimoClient.Vote(ctx, &rarimotypes.QueryGetVoteRequest{
+				Operation: op.Index,
+				Validator: c.voter.Sender(),
+			})
+
+			if err == nil {
+				c.log.WithField("index", op.Index).Info("Operation already voted")
+				continue
+			}
+
+			select {
+			case c.workers <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+
+			wg.Add(1)
+			go func(op rarimotypes.Operation) {
+				defer func() {
+					<-c.workers
+					wg.Done()
+				}()
+				process(ctx, c.voter, c.log, op)
+			}(op)
+		}
+
+		nextKey = operations.Pagination.NextKey
+		if nextKey == nil {
+			wg.Wait()
+			c.log.Infof("Finished catchup unvoted operations")
+			return
+		}
+	}
+}
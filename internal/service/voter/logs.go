@@ -0,0 +1,75 @@
+package voter
+
+import (
+	"github.com/olegfomenko/solana-go"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/config"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/solana-program-go/contracts/bridge"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// LogsVerifier cross-checks deposit instructions against the transaction log messages.
+type LogsVerifier struct {
+	program     solana.PublicKey
+	depositLogs map[bridge.Instruction]string
+}
+
+func NewLogsVerifier(cfg config.Config) *LogsVerifier {
+	return &LogsVerifier{
+		program: cfg.ListenConf().ProgramId,
+		depositLogs: map[bridge.Instruction]string{
+			bridge.InstructionDepositNative: cfg.ListenConf().NativeDepositLog,
+			bridge.InstructionDepositFT:     cfg.ListenConf().FTDepositLog,
+			bridge.InstructionDepositNFT:    cfg.ListenConf().NFTDepositLog,
+		},
+	}
+}
+
+// Verify checks that the instruction with provided index was executed by the bridge program
+// and finished successfully according to the transaction logs. If the deposit log message is configured
+// for the instruction type, the bridge program should also log it during that invocation.
+// Returns verifiers.ErrWrongOperationContent in case of any mismatch.
+func (l *LogsVerifier) Verify(tx *service.Transaction, index int) error {
+	if tx.Meta == nil || tx.Meta.LogMessages == nil {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "transaction logs are not available")
+	}
+
+	invocations, truncated := service.ParseInvocations(tx.Meta.LogMessages)
+	if index >= len(invocations) {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "instruction invocation not found in logs", logan.F{
+			"invocations": len(invocations),
+			"truncated":   truncated,
+		})
+	}
+
+	invocation := invocations[index]
+	if invocation.Program != l.program {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "logged invocation program mismatch", logan.F{
+			"program": invocation.Program.String(),
+		})
+	}
+
+	if !invocation.Success {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "bridge invocation has not finished successfully")
+	}
+
+	instruction := tx.Message.Instructions[index]
+	if len(instruction.Data) == 0 {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "empty instruction data")
+	}
+
+	expected := l.depositLogs[bridge.Instruction(instruction.Data[DataInstructionCodeIndex])]
+	if expected == "" {
+		return nil
+	}
+
+	for _, log := range invocation.Logs {
+		if log == expected {
+			return nil
+		}
+	}
+
+	return errors.Wrap(verifiers.ErrWrongOperationContent, "deposit log not found", logan.F{"expected": expected})
+}
@@ -0,0 +1,172 @@
+package voter
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/near/borsh-go"
+	"github.com/olegfomenko/solana-go"
+	"github.com/rarimo/solana-program-go/metaplex"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Token standards missing in the metaplex package
+const (
+	ProgrammableNonFungible        metaplex.TokenStandard = 4
+	ProgrammableNonFungibleEdition metaplex.TokenStandard = 5
+)
+
+const KeyTokenRecord metaplex.Key = 11
+
+const TokenRecordPrefix = "token_record"
+
+type TokenState uint8
+
+const (
+	TokenStateUnlocked TokenState = iota
+	TokenStateLocked
+	TokenStateListed
+)
+
+type EditionKind string
+
+const (
+	// EditionNone is the legacy token without edition account
+	EditionNone EditionKind = "none"
+	// EditionUnique is the master edition with zero max supply (1/1)
+	EditionUnique EditionKind = "unique"
+	// EditionMaster is the master edition that allows printing
+	EditionMaster EditionKind = "master"
+	// EditionPrint is the edition printed from the master edition
+	EditionPrint EditionKind = "print"
+)
+
+type CollectionDetails struct {
+	Enum borsh.Enum `borsh_enum:"true"`
+	V1   struct{ Size uint64 }
+	V2   struct{ Padding [8]byte }
+}
+
+type ProgrammableConfig struct {
+	Enum borsh.Enum `borsh_enum:"true"`
+	V1   struct{ RuleSet *solana.PublicKey }
+}
+
+// Metadata is the Metaplex token metadata with the fields added for the programmable NFTs.
+// Note: borsh decodes None options as pointers to zero values.
+type Metadata struct {
+	metaplex.Metadata
+	CollectionDetails  *CollectionDetails
+	ProgrammableConfig *ProgrammableConfig
+}
+
+func (m *Metadata) IsProgrammable() bool {
+	return m.TokenStandard != nil &&
+		(*m.TokenStandard == ProgrammableNonFungible || *m.TokenStandard == ProgrammableNonFungibleEdition)
+}
+
+func (m *Metadata) IsFungible() bool {
+	return m.TokenStandard != nil &&
+		(*m.TokenStandard == metaplex.Fungible || *m.TokenStandard == metaplex.FungibleAsset)
+}
+
+// RuleSet returns the programmable NFT rule set or zero key if it is not set.
+func (m *Metadata) RuleSet() solana.PublicKey {
+	if m.ProgrammableConfig == nil || m.ProgrammableConfig.V1.RuleSet == nil {
+		return solana.PublicKey{}
+	}
+
+	return *m.ProgrammableConfig.V1.RuleSet
+}
+
+// CollectionAddress returns the hex encoded verified collection address or empty string.
+func (m *Metadata) CollectionAddress() string {
+	if m.Collection == nil || !m.Collection.Verified {
+		return ""
+	}
+
+	return hexutil.Encode(m.Collection.Address.Bytes())
+}
+
+// Edition is the decoded master or print edition account.
+type Edition struct {
+	Kind EditionKind
+	// Supply and max supply of the master edition (max supply is <nil> for unlimited editions)
+	Supply    uint64
+	MaxSupply *uint64
+	// Parent master edition account and the number of the print edition
+	Parent solana.PublicKey
+	Number uint64
+}
+
+// ParseEdition decodes master edition (V1 or V2) or print edition account data.
+// Options are decoded manually to distinguish unlimited and 1/1 master editions.
+func ParseEdition(data []byte) (*Edition, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty edition data")
+	}
+
+	switch metaplex.Key(data[0]) {
+	case metaplex.KeyMasterEditionV1, metaplex.KeyMasterEditionV2:
+		// key + supply + option flag
+		if len(data) < 1+8+1 {
+			return nil, errors.New("invalid master edition length")
+		}
+
+		edition := &Edition{
+			Kind:   EditionMaster,
+			Supply: binary.LittleEndian.Uint64(data[1:9]),
+		}
+
+		if data[9] == 1 {
+			if len(data) < 1+8+1+8 {
+				return nil, errors.New("invalid master edition length")
+			}
+
+			maxSupply := binary.LittleEndian.Uint64(data[10:18])
+			edition.MaxSupply = &maxSupply
+
+			if maxSupply == 0 {
+				edition.Kind = EditionUnique
+			}
+		}
+
+		return edition, nil
+	case metaplex.KeyEditionV1:
+		// key + parent + edition number
+		if len(data) < 1+32+8 {
+			return nil, errors.New("invalid edition length")
+		}
+
+		return &Edition{
+			Kind:   EditionPrint,
+			Parent: solana.PublicKeyFromBytes(data[1:33]),
+			Number: binary.LittleEndian.Uint64(data[33:41]),
+		}, nil
+	}
+
+	return nil, errors.From(errors.New("account is not an edition"), logan.F{"key": data[0]})
+}
+
+// TokenRecord is the programmable NFT token account state.
+type TokenRecord struct {
+	Key             metaplex.Key
+	Bump            uint8
+	State           TokenState
+	RuleSetRevision *uint64
+	Delegate        *solana.PublicKey
+	DelegateRole    *uint8
+	LockedTransfer  *solana.PublicKey
+}
+
+func FindTokenRecordAddress(mint, token solana.PublicKey) (solana.PublicKey, uint8, error) {
+	seed := [][]byte{
+		[]byte(metaplex.MetadataPrefix),
+		solana.TokenMetadataProgramID[:],
+		mint[:],
+		[]byte(TokenRecordPrefix),
+		token[:],
+	}
+	return solana.FindProgramAddress(seed, solana.TokenMetadataProgramID)
+}
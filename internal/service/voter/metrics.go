@@ -0,0 +1,24 @@
+package voter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Collection policy decisions for the deposited NFTs
+const (
+	CollectionDecisionVerified    = "verified"
+	CollectionDecisionStandalone  = "standalone"
+	CollectionDecisionAllowlisted = "allowlisted"
+	CollectionDecisionRejected    = "rejected"
+)
+
+var (
+	CollectionDecisionsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nft_collection_decisions_total",
+	}, []string{"policy", "decision"})
+
+	FlaggedDepositsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "deposits_flagged_for_refund_total",
+	}, []string{"reason"})
+)
@@ -0,0 +1,81 @@
+package voter
+
+import (
+	"context"
+	goerr "errors"
+
+	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deposits targeting networks or tokens unknown to core can never be bridged and should be refunded.
+var (
+	ErrUnknownNetwork      = goerr.New("unknown network")
+	ErrNetworkNotSupported = goerr.New("network not supported for this token")
+	ErrTokenNotMapped      = goerr.New("token not mapped")
+)
+
+// GetTargetNetwork returns the deposit target network registered in core.
+// Returns ErrUnknownNetwork if network is not registered and ErrNetworkNotSupported if it does not support bridging.
+func GetTargetNetwork(ctx context.Context, tokens tokentypes.QueryClient, name string) (*tokentypes.Network, error) {
+	networkResp, err := tokens.NetworkParams(ctx, &tokentypes.QueryNetworkParamsRequest{Name: name})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.Wrap(ErrUnknownNetwork, "network is not registered in core", logan.F{"network": name})
+		}
+
+		return nil, errors.Wrap(err, "error fetching target network params", logan.F{"network": name})
+	}
+
+	if !hasParams(&networkResp.Params, tokentypes.NetworkParamType_BRIDGE) {
+		return nil, errors.Wrap(ErrNetworkNotSupported, "network does not support bridge operations", logan.F{"network": name})
+	}
+
+	return &networkResp.Params, nil
+}
+
+// getTargetItem returns the target network index of the fungible or native token item.
+// Returns ErrTokenNotMapped if token is not registered in core and ErrNetworkNotSupported if item has no target network index.
+func getTargetItem(ctx context.Context, tokens tokentypes.QueryClient, from *tokentypes.OnChainItemIndex, network string) (*tokentypes.OnChainItemIndex, error) {
+	fromOnChainResp, err := tokens.OnChainItem(ctx, &tokentypes.QueryGetOnChainItemRequest{Chain: from.Chain, Address: from.Address, TokenID: from.TokenID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.Wrap(ErrTokenNotMapped, "on chain item is not registered in core", logan.F{"address": from.Address})
+		}
+
+		return nil, errors.Wrap(err, "error fetching on chain item")
+	}
+
+	itemResp, err := tokens.Item(ctx, &tokentypes.QueryGetItemRequest{Index: fromOnChainResp.Item.Item})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.Wrap(ErrTokenNotMapped, "item is not registered in core", logan.F{"item": fromOnChainResp.Item.Item})
+		}
+
+		return nil, errors.Wrap(err, "error fetching item")
+	}
+
+	for _, index := range itemResp.Item.OnChain {
+		if index.Chain == network {
+			return index, nil
+		}
+	}
+
+	return nil, errors.Wrap(ErrNetworkNotSupported, "item has no on chain index for target network", logan.F{
+		"item":    fromOnChainResp.Item.Item,
+		"network": network,
+	})
+}
+
+func hasParams(network *tokentypes.Network, typ tokentypes.NetworkParamType) bool {
+	for _, params := range network.Params {
+		if params.Type == typ {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package voter
+
+import (
+	goerr "errors"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/olegfomenko/solana-go"
+	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// ErrInvalidReceiver is returned for deposits whose funds can not be withdrawn on the target network.
+// Such deposits should be refunded instead of creating the transfer operation.
+var ErrInvalidReceiver = goerr.New("invalid receiver address")
+
+const (
+	nearAccountMinLength = 2
+	nearAccountMaxLength = 64
+)
+
+var nearAccountRegexp = regexp.MustCompile(`^(([a-z\d]+[\-_])*[a-z\d]+\.)*([a-z\d]+[\-_])*[a-z\d]+$`)
+
+// ValidateReceiver checks the receiver address against the format rules of the target network type registered in core.
+// Core requires the receiver to be hex encoded, the decoded bytes should be the address of the target network.
+// Returns ErrInvalidReceiver if the address is malformed.
+func ValidateReceiver(network *tokentypes.Network, receiver string) error {
+	var valid bool
+
+	address, err := hexutil.Decode(receiver)
+	if err == nil {
+		switch network.Type {
+		case tokentypes.NetworkType_EVM:
+			valid = isEVMAddress(receiver, address)
+		case tokentypes.NetworkType_Solana:
+			valid = len(address) == solana.PublicKeyLength
+		case tokentypes.NetworkType_Near:
+			valid = isNearAccount(string(address))
+		default:
+			// no known format rules
+			valid = len(address) > 0
+		}
+	}
+
+	if !valid {
+		return errors.Wrap(ErrInvalidReceiver, "receiver does not match target network format", logan.F{
+			"network":      network.Name,
+			"network_type": network.Type.String(),
+			"receiver":     receiver,
+		})
+	}
+
+	return nil
+}
+
+// isEVMAddress accepts 20 bytes addresses; mixed-case addresses should have the valid EIP-55 checksum.
+func isEVMAddress(receiver string, address []byte) bool {
+	if len(address) != common.AddressLength {
+		return false
+	}
+
+	hex := receiver[2:]
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return true
+	}
+
+	return common.BytesToAddress(address).Hex() == receiver
+}
+
+// isNearAccount accepts named and implicit NEAR account IDs.
+func isNearAccount(account string) bool {
+	return len(account) >= nearAccountMinLength &&
+		len(account) <= nearAccountMaxLength &&
+		nearAccountRegexp.MatchString(account)
+}
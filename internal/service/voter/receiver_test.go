@@ -0,0 +1,48 @@
+package voter
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func TestValidateReceiver(t *testing.T) {
+	evm := &tokentypes.Network{Name: "Ethereum", Type: tokentypes.NetworkType_EVM}
+	sol := &tokentypes.Network{Name: "Solana", Type: tokentypes.NetworkType_Solana}
+	near := &tokentypes.Network{Name: "Near", Type: tokentypes.NetworkType_Near}
+
+	cases := []struct {
+		name     string
+		network  *tokentypes.Network
+		receiver string
+		valid    bool
+	}{
+		{name: "evm lower case", network: evm, receiver: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", valid: true},
+		{name: "evm checksum", network: evm, receiver: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", valid: true},
+		{name: "evm wrong checksum", network: evm, receiver: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"},
+		{name: "evm short", network: evm, receiver: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea"},
+		{name: "evm without prefix", network: evm, receiver: "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{name: "solana hex key", network: sol, receiver: hexutil.Encode(make([]byte, 32)), valid: true},
+		{name: "solana base58 key", network: sol, receiver: "11111111111111111111111111111111"},
+		{name: "solana short key", network: sol, receiver: hexutil.Encode(make([]byte, 31))},
+		{name: "near named account", network: near, receiver: hexutil.Encode([]byte("alice.near")), valid: true},
+		{name: "near not hex encoded", network: near, receiver: "alice.near"},
+		{name: "near invalid account", network: near, receiver: hexutil.Encode([]byte("Alice..near"))},
+		{name: "empty", network: evm, receiver: ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateReceiver(c.network, c.receiver)
+			if c.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !c.valid && errors.Cause(err) != ErrInvalidReceiver {
+				t.Fatalf("expected ErrInvalidReceiver, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package voter
+
+import (
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Reasons of the deposits flagged for refund
+const (
+	RefundReasonInvalidReceiver     = "invalid_receiver"
+	RefundReasonInvalidBundle       = "invalid_bundle"
+	RefundReasonUnknownNetwork      = "unknown_network"
+	RefundReasonNetworkNotSupported = "network_not_supported"
+	RefundReasonTokenNotMapped      = "token_not_mapped"
+)
+
+// RefundReason returns the reason of the refund if the error means that the deposit can never be bridged.
+func RefundReason(err error) (string, bool) {
+	switch errors.Cause(err) {
+	case ErrInvalidReceiver:
+		return RefundReasonInvalidReceiver, true
+	case ErrInvalidBundle:
+		return RefundReasonInvalidBundle, true
+	case ErrUnknownNetwork:
+		return RefundReasonUnknownNetwork, true
+	case ErrNetworkNotSupported:
+		return RefundReasonNetworkNotSupported, true
+	case ErrTokenNotMapped:
+		return RefundReasonTokenNotMapped, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,316 @@
+package voter
+
+import (
+	"context"
+	goerr "errors"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/gogo/protobuf/proto"
+	oracletypes "github.com/rarimo/rarimo-core/x/oraclemanager/types"
+	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	"github.com/rarimo/saver-grpc-lib/broadcaster"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/config"
+	"github.com/rarimo/sol-saver-svc/internal/service/pause"
+	"github.com/tendermint/tendermint/rpc/client/http"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrOperationNotFound  = goerr.New("operation not found")
+	ErrOperationProcessed = goerr.New("operation is already processed")
+	ErrOperationVoted     = goerr.New("operation is already voted")
+	// ErrMalformedOperation is returned if the operation details stored in core can not be decoded
+	ErrMalformedOperation = goerr.New("malformed operation")
+	// ErrUnavailable is returned if the operation can not be revoted because of core, Solana RPC or broadcaster failure
+	ErrUnavailable = goerr.New("dependency unavailable")
+	// ErrTooManyOperations is returned if the filter matches more operations than can be revoted in the single batch
+	ErrTooManyOperations = goerr.New("too many operations")
+)
+
+// RevoteFilter selects the unvoted transfer operations from this chain created in the core blocks range (inclusive).
+// ToHeight is the latest block if zero.
+type RevoteFilter struct {
+	FromHeight int64
+	ToHeight   int64
+}
+
+// RevoteResult is the result of the single operation revote.
+// Err is ErrOperationProcessed or ErrOperationVoted if the operation was skipped,
+// ErrUnavailable if the revote can be retried later.
+// If the operation content does not match the deposit, the NO vote is broadcasted
+// and Err is verifiers.ErrWrongOperationContent with the verification failure reason.
+type RevoteResult struct {
+	Index string
+	Vote  rarimotypes.VoteType
+	Err   error
+}
+
+// Revoter verifies and votes for the batches of transfer operations.
+// Unlike voter.Voter it reports the verification errors instead of only logging them.
+type Revoter struct {
+	rarimoClient rarimotypes.QueryClient
+	tendermint   *http.HTTP
+	operator     verifiers.TransferOperator
+	broadcaster  broadcaster.Broadcaster
+	chain        string
+	workers      int
+	maxBatch     int
+	log          *logan.Entry
+}
+
+func NewRevoter(cfg config.Config, operator verifiers.TransferOperator) *Revoter {
+	return &Revoter{
+		rarimoClient: rarimotypes.NewQueryClient(cfg.Cosmos()),
+		tendermint:   cfg.Tendermint(),
+		operator:     operator,
+		broadcaster:  pause.NewBroadcaster(cfg.Broadcaster(), cfg.Pause(), pause.Voter),
+		chain:        cfg.ListenConf().Chain,
+		workers:      cfg.VoterConf().Workers,
+		maxBatch:     cfg.VoterConf().MaxBatch,
+		log:          cfg.Log(),
+	}
+}
+
+// MaxBatch returns the max amount of operations revoted in the single batch.
+func (r *Revoter) MaxBatch() int {
+	return r.maxBatch
+}
+
+// Find returns the indexes of unvoted operations matching the filter.
+// Operations store the creation block time, so the heights range is converted to the block times range.
+// Core returns operations ordered by index, not by time, so all of them are scanned; the search is stopped
+// with ErrTooManyOperations as soon as more than MaxBatch unvoted operations are found.
+func (r *Revoter) Find(ctx context.Context, filter RevoteFilter) ([]string, error) {
+	from, err := r.blockTime(ctx, &filter.FromHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting from block time", logan.F{"height": filter.FromHeight})
+	}
+
+	var toHeight *int64
+	if filter.ToHeight != 0 {
+		toHeight = &filter.ToHeight
+	}
+
+	to, err := r.blockTime(ctx, toHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting to block time", logan.F{"height": filter.ToHeight})
+	}
+
+	var indexes []string
+	var nextKey []byte
+
+	for {
+		operations, err := r.rarimoClient.OperationAll(ctx, &rarimotypes.QueryAllOperationRequest{
+			Pagination: &query.PageRequest{
+				Key: nextKey,
+			},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get operations")
+		}
+
+		for _, op := range operations.Operation {
+			if op.OperationType != rarimotypes.OpType_TRANSFER || op.Status != rarimotypes.OpStatus_INITIALIZED {
+				continue
+			}
+
+			if op.Timestamp < from || op.Timestamp > to {
+				continue
+			}
+
+			transfer := new(rarimotypes.Transfer)
+			if err := proto.Unmarshal(op.Details.Value, transfer); err != nil {
+				r.log.WithError(err).WithField("index", op.Index).Error("failed to unmarshal transfer")
+				continue
+			}
+
+			if transfer.From.Chain != r.chain {
+				continue
+			}
+
+			voted, err := r.voted(ctx, op.Index)
+			if err != nil {
+				return nil, err
+			}
+
+			if voted {
+				continue
+			}
+
+			if len(indexes) == r.maxBatch {
+				return nil, errors.Wrap(ErrTooManyOperations, "narrow the blocks range", logan.F{"limit": r.maxBatch})
+			}
+
+			indexes = append(indexes, op.Index)
+		}
+
+		nextKey = operations.Pagination.NextKey
+		if nextKey == nil {
+			return indexes, nil
+		}
+	}
+}
+
+// Revote processes the operations concurrently by the configured amount of voter workers
+// and sends the result of every operation to the channel as soon as it is processed.
+// Channel is closed after all operations are processed.
+func (r *Revoter) Revote(ctx context.Context, indexes []string) <-chan RevoteResult {
+	queue := make(chan string, len(indexes))
+	for _, index := range indexes {
+		queue <- index
+	}
+	close(queue)
+
+	results := make(chan RevoteResult, len(indexes))
+
+	var wg sync.WaitGroup
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				results <- r.RevoteOperation(ctx, index)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// RevoteOperation verifies the initialized operation that has not been voted by this service yet and broadcasts the vote.
+func (r *Revoter) RevoteOperation(ctx context.Context, index string) (result RevoteResult) {
+	result.Index = index
+
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			r.log.WithRecover(rvr).WithFields(logan.F{"index": index}).Error("operation revote panicked")
+			result.Err = errors.New("operation revote panicked")
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return
+	}
+
+	op, err := r.rarimoClient.Operation(ctx, &rarimotypes.QueryGetOperationRequest{Index: index})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			result.Err = ErrOperationNotFound
+			return
+		}
+
+		result.Err = unavailable(err, "error fetching operation")
+		return
+	}
+
+	if op.Operation.Status != rarimotypes.OpStatus_INITIALIZED {
+		result.Err = ErrOperationProcessed
+		return
+	}
+
+	voted, err := r.voted(ctx, index)
+	if err != nil {
+		result.Err = err
+		return
+	}
+
+	if voted {
+		result.Err = ErrOperationVoted
+		return
+	}
+
+	result.Vote, result.Err = r.vote(ctx, op.Operation)
+	return
+}
+
+// vote verifies the operation in the same way as verifiers.TransferVerifier does, but keeps the reason of the NO vote.
+func (r *Revoter) vote(ctx context.Context, operation rarimotypes.Operation) (rarimotypes.VoteType, error) {
+	if operation.OperationType != rarimotypes.OpType_TRANSFER {
+		return 0, verifiers.ErrInvalidOperationType
+	}
+
+	transfer := new(rarimotypes.Transfer)
+	if err := proto.Unmarshal(operation.Details.Value, transfer); err != nil {
+		return 0, errors.Wrap(ErrMalformedOperation, err.Error())
+	}
+
+	vote := rarimotypes.VoteType_YES
+
+	verifyErr := r.operator.VerifyTransfer(ctx, transfer.Tx, transfer.EventId, transfer)
+	if verifyErr != nil {
+		if errors.Cause(verifyErr) != verifiers.ErrWrongOperationContent {
+			return 0, unavailable(verifyErr, "verification failed")
+		}
+
+		vote = rarimotypes.VoteType_NO
+	}
+
+	log := r.log.WithFields(logan.F{"index": operation.Index, "vote": vote.String()})
+	if verifyErr != nil {
+		log = log.WithField("reason", verifyErr.Error())
+	}
+
+	log.Info("Operation revoted")
+
+	err := r.broadcaster.BroadcastTx(ctx, &oracletypes.MsgVote{
+		Index: &oracletypes.OracleIndex{
+			Chain:   r.chain,
+			Account: r.broadcaster.Sender(),
+		},
+		Operation: operation.Index,
+		Vote:      vote,
+	})
+	if err != nil {
+		return vote, unavailable(err, "error broadcasting vote")
+	}
+
+	return vote, verifyErr
+}
+
+func (r *Revoter) voted(ctx context.Context, index string) (bool, error) {
+	_, err := r.rarimoClient.Vote(ctx, &rarimotypes.QueryGetVoteRequest{
+		Operation: index,
+		Validator: r.broadcaster.Sender(),
+	})
+	if err == nil {
+		return true, nil
+	}
+
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+
+	return false, unavailable(err, "error fetching vote")
+}
+
+// unavailable wraps the failure of core, Solana RPC or broadcaster into ErrUnavailable,
+// keeping the cancellation, timeout and other known failure reasons.
+func unavailable(err error, msg string) error {
+	switch errors.Cause(err) {
+	case context.Canceled, context.DeadlineExceeded, pause.ErrPaused, verifiers.ErrUnsupportedNetwork:
+		return errors.Wrap(err, msg)
+	}
+
+	return errors.Wrap(ErrUnavailable, msg+": "+err.Error())
+}
+
+// blockTime returns the unix time of the block at height or the latest block if height is <nil>.
+func (r *Revoter) blockTime(ctx context.Context, height *int64) (uint64, error) {
+	block, err := r.tendermint.Block(ctx, height)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(block.Block.Time.Unix()), nil
+}
@@ -0,0 +1,130 @@
+package voter
+
+import (
+	"context"
+	"fmt"
+
+	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
+	libvoter "github.com/rarimo/saver-grpc-lib/voter"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+	"google.golang.org/grpc"
+)
+
+const (
+	OpServiceName = "op-subscriber"
+	OpPoolSize    = 1000
+)
+
+// Subscriber subscribes to the NewOperation events on the tendermint core.
+// Unlike the saver-grpc-lib one, it processes operations concurrently by the limited amount of workers,
+// so the slow verification does not delay other votes.
+type Subscriber struct {
+	voter   *libvoter.Voter
+	client  rpcclient.EventsClient
+	rarimo  *grpc.ClientConn
+	log     *logan.Entry
+	cfg     libvoter.SubscriberConfig
+	workers chan struct{}
+}
+
+func NewTransferSubscriber(voter *libvoter.Voter, client rpcclient.EventsClient, rarimo *grpc.ClientConn, log *logan.Entry, cfg libvoter.SubscriberConfig, workers int) *Subscriber {
+	return &Subscriber{
+		voter:   voter,
+		client:  client,
+		rarimo:  rarimo,
+		log:     log,
+		cfg:     cfg,
+		workers: make(chan struct{}, workers),
+	}
+}
+
+func (s *Subscriber) Run(ctx context.Context) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			s.log.WithRecover(rvr).Error("Subscriber panicked")
+		}
+	}()
+
+	running.WithBackOff(ctx,
+		s.log.WithField("who", "subscriber"),
+		"subscriber",
+		s.runOnce,
+		s.cfg.MinRetryPeriod, s.cfg.MinRetryPeriod, s.cfg.MaxRetryPeriod)
+}
+
+func (s *Subscriber) runOnce(ctx context.Context) error {
+	s.log.Infof("Starting subscription for the new unvoted operations")
+
+	out, err := s.client.Subscribe(ctx, OpServiceName, libvoter.OpQueryTransfer, OpPoolSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe to the new operations")
+	}
+
+	queryClient := rarimotypes.NewQueryClient(s.rarimo)
+
+	for {
+		eventData := readOneEvent(ctx, out)
+		if eventData == nil {
+			s.log.Debug("context canceled, exiting")
+			return nil
+		}
+
+		for _, index := range eventData.Events[fmt.Sprintf("%s.%s", rarimotypes.EventTypeNewOperation, rarimotypes.AttributeKeyOperationId)] {
+			s.log.
+				WithFields(logan.F{"index": index}).
+				Info("New operation found")
+
+			op, err := queryClient.Operation(ctx, &rarimotypes.QueryGetOperationRequest{Index: index})
+			if err != nil {
+				s.log.
+					WithError(err).
+					WithFields(logan.F{"index": index}).
+					Errorf("failed to fetch operation data")
+				continue
+			}
+
+			if op.Operation.Status != rarimotypes.OpStatus_INITIALIZED {
+				continue
+			}
+
+			select {
+			case s.workers <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+
+			go func(op rarimotypes.Operation) {
+				defer func() { <-s.workers }()
+				process(ctx, s.voter, s.log, op)
+			}(op.Operation)
+		}
+	}
+}
+
+func process(ctx context.Context, voter *libvoter.Voter, log *logan.Entry, op rarimotypes.Operation) {
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			log.WithRecover(rvr).WithFields(logan.F{"index": op.Index}).Error("operation processing panicked")
+		}
+	}()
+
+	if err := voter.Process(ctx, op); err != nil {
+		log.
+			WithError(err).
+			WithFields(logan.F{"index": op.Index}).
+			Errorf("failed to process operation")
+	}
+}
+
+func readOneEvent(ctx context.Context, from <-chan coretypes.ResultEvent) *coretypes.ResultEvent {
+	select {
+	case <-ctx.Done():
+		return nil
+	case e := <-from:
+		return &e
+	}
+}
@@ -2,7 +2,8 @@ package voter
 
 import (
 	"context"
-	"fmt"
+	"math/big"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/gogo/protobuf/proto"
@@ -12,6 +13,7 @@ import (
 	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
 	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service"
 	"github.com/rarimo/solana-program-go/contracts/bridge"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
@@ -19,21 +21,27 @@ import (
 )
 
 type ftOperator struct {
-	chain  string
-	log    *logan.Entry
-	rarimo *grpc.ClientConn
+	chain   string
+	log     *logan.Entry
+	solana  *service.Quorum
+	rarimo  *grpc.ClientConn
+	tokens  tokentypes.QueryClient
+	bundles *BundleVerifier
 }
 
-func NewFTOperator(chain string, log *logan.Entry, rarimo *grpc.ClientConn) *ftOperator {
+func NewFTOperator(chain string, log *logan.Entry, solana *service.Quorum, rarimo *grpc.ClientConn, tokens tokentypes.QueryClient, bundles *BundleVerifier) *ftOperator {
 	return &ftOperator{
-		chain:  chain,
-		log:    log,
-		rarimo: rarimo,
+		chain:   chain,
+		log:     log,
+		solana:  solana,
+		rarimo:  rarimo,
+		tokens:  tokens,
+		bundles: bundles,
 	}
 }
 
-func (f *ftOperator) ParseTransaction(ctx context.Context, accounts []solana.PublicKey, instruction solana.CompiledInstruction, transfer *rarimotypes.Transfer) error {
-	msg, err := f.GetMessage(ctx, accounts, instruction)
+func (f *ftOperator) ParseTransaction(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction, transfer *rarimotypes.Transfer) error {
+	msg, err := f.GetMessage(ctx, tx, instruction)
 	if err != nil {
 		return errors.Wrap(err, "error getting message")
 	}
@@ -47,16 +55,35 @@ func (f *ftOperator) ParseTransaction(ctx context.Context, accounts []solana.Pub
 	}
 
 	if !proto.Equal(&transferResp.Transfer, transfer) {
-		return verifiers.ErrWrongOperationContent
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "operation content does not match the deposit")
 	}
 
 	return nil
 }
 
-func (f *ftOperator) GetMessage(ctx context.Context, accounts []solana.PublicKey, instruction solana.CompiledInstruction) (*oracletypes.MsgCreateTransferOp, error) {
+func (f *ftOperator) GetMessage(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction) (*oracletypes.MsgCreateTransferOp, error) {
+	accounts, err := service.GetInstructionAccounts(tx.Message.AccountKeys, instruction.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
 	var args bridge.DepositFTArgs
 	if err := borsh.Deserialize(&args, instruction.Data); err != nil {
-		return nil, errors.Wrap(err, "error desser tx args")
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "error desser tx args: "+err.Error())
+	}
+
+	network, err := GetTargetNetwork(ctx, f.tokens, args.NetworkTo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateReceiver(network, args.ReceiverAddress); err != nil {
+		return nil, err
+	}
+
+	hasBundle, err := f.bundles.Verify(network, args.BundleData, args.BundleSeed)
+	if err != nil {
+		return nil, err
 	}
 
 	address := hexutil.Encode(accounts[bridge.DepositFTMintIndex].Bytes())
@@ -67,20 +94,29 @@ func (f *ftOperator) GetMessage(ctx context.Context, accounts []solana.PublicKey
 		TokenID: "",
 	}
 
-	to, err := f.getTo(ctx, &from, args.NetworkTo)
-	if to == nil {
+	to, err := getTargetItem(ctx, f.tokens, &from, args.NetworkTo)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := f.getAmount(ctx, tx, instruction, args.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.verifyDecimals(ctx, accounts[bridge.DepositFTMintIndex], &from, to, amount); err != nil {
 		return nil, err
 	}
 
 	msg := &oracletypes.MsgCreateTransferOp{
 		Sender:   accounts[bridge.DepositFTOwnerIndex].String(),
 		Receiver: args.ReceiverAddress,
-		Amount:   fmt.Sprint(args.Amount),
+		Amount:   strconv.FormatUint(amount, 10),
 		From:     from,
 		To:       *to,
 	}
 
-	if args.BundleData != nil && len(*args.BundleData) > 0 && args.BundleSeed != nil {
+	if hasBundle {
 		msg.BundleData = hexutil.Encode(*args.BundleData)
 		msg.BundleSalt = hexutil.Encode((*args.BundleSeed)[:])
 	}
@@ -88,24 +124,120 @@ func (f *ftOperator) GetMessage(ctx context.Context, accounts []solana.PublicKey
 	return msg, nil
 }
 
-func (f *ftOperator) getTo(ctx context.Context, from *tokentypes.OnChainItemIndex, chain string) (*tokentypes.OnChainItemIndex, error) {
-	fromOnChainResp, err := tokentypes.NewQueryClient(f.rarimo).OnChainItem(ctx, &tokentypes.QueryGetOnChainItemRequest{Chain: f.chain, Address: from.Address})
+// getAmount returns the amount of tokens the bridge vault has actually received.
+// For Token-2022 mints it is the deposited amount minus the transfer fee.
+func (f *ftOperator) getAmount(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction, amount uint64) (uint64, error) {
+	program, err := accountIndex(instruction, DepositFTTokenProgramIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	if int(program) >= len(tx.Message.AccountKeys) {
+		return 0, errors.Wrap(verifiers.ErrWrongOperationContent, "invalid token program index")
+	}
+
+	switch tx.Message.AccountKeys[program] {
+	case solana.TokenProgramID:
+		return amount, nil
+	case service.Token2022ProgramID:
+	default:
+		return 0, errors.Wrap(verifiers.ErrWrongOperationContent, "unknown token program", logan.F{
+			"program": tx.Message.AccountKeys[program].String(),
+		})
+	}
+
+	mint, err := accountIndex(instruction, bridge.DepositFTMintIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	vault, err := accountIndex(instruction, bridge.DepositFTBridgeAssocIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	if int(mint) >= len(tx.Message.AccountKeys) || tx.Meta == nil {
+		return 0, errors.Wrap(verifiers.ErrWrongOperationContent, "deposit data is not available")
+	}
+
+	fees, err := VerifyToken2022Mint(ctx, f.solana, tx.Message.AccountKeys[mint])
+	if err != nil {
+		return 0, err
+	}
+
+	pre, err := tokenBalance(tx.Meta.PreTokenBalances, vault, tx.Message.AccountKeys[mint])
+	if err != nil {
+		return 0, err
+	}
+
+	post, err := tokenBalance(tx.Meta.PostTokenBalances, vault, tx.Message.AccountKeys[mint])
+	if err != nil {
+		return 0, err
+	}
+
+	received := new(big.Int).Sub(post, pre)
+	if !received.IsUint64() {
+		return 0, errors.Wrap(verifiers.ErrWrongOperationContent, "invalid vault balance change", logan.F{"received": received.String()})
+	}
+
+	if err := verifyTransferFee(fees, amount, received.Uint64()); err != nil {
+		return 0, err
+	}
+
+	return received.Uint64(), nil
+}
+
+// verifyDecimals checks that the mint decimals match the decimals registered in core for the deposited token.
+// Core scales the amount to the target token decimals itself truncating the remainder,
+// so transfers that lose precision are refused.
+func (f *ftOperator) verifyDecimals(ctx context.Context, mint solana.PublicKey, from, to *tokentypes.OnChainItemIndex, amount uint64) error {
+	mintInfo, err := f.solana.GetAccountData(ctx, mint)
 	if err != nil {
-		f.log.WithError(err).Error("error fetching on chain item")
-		return nil, verifiers.ErrWrongOperationContent
+		return errors.Wrap(err, "error getting mint account")
 	}
 
-	itemResp, err := tokentypes.NewQueryClient(f.rarimo).Item(ctx, &tokentypes.QueryGetItemRequest{Index: fromOnChainResp.Item.Item})
+	mintDecimals, err := service.ParseMintDecimals(mintInfo)
 	if err != nil {
-		f.log.WithError(err).Error("error fetching item")
-		return nil, verifiers.ErrWrongOperationContent
+		return errors.Wrap(verifiers.ErrWrongOperationContent, err.Error())
 	}
 
-	for _, index := range itemResp.Item.OnChain {
-		if index.Chain == chain {
-			return index, nil
-		}
+	fromData, err := f.tokens.CollectionData(ctx, &tokentypes.QueryGetCollectionDataRequest{Chain: from.Chain, Address: from.Address})
+	if err != nil {
+		return errors.Wrap(err, "error fetching collection data")
 	}
 
-	return nil, verifiers.ErrWrongOperationContent
+	toData, err := f.tokens.CollectionData(ctx, &tokentypes.QueryGetCollectionDataRequest{Chain: to.Chain, Address: to.Address})
+	if err != nil {
+		return errors.Wrap(err, "error fetching target collection data")
+	}
+
+	return checkDecimals(amount, mintDecimals, fromData.Data.Decimals, toData.Data.Decimals)
+}
+
+func checkDecimals(amount uint64, mintDecimals uint8, fromDecimals, toDecimals uint32) error {
+	if uint32(mintDecimals) != fromDecimals {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "mint decimals do not match core collection data", logan.F{
+			"mint_decimals": mintDecimals,
+			"from_decimals": fromDecimals,
+		})
+	}
+
+	if fromDecimals <= toDecimals {
+		return nil
+	}
+
+	remainder := new(big.Int).Rem(new(big.Int).SetUint64(amount), pow10(int64(fromDecimals-toDecimals)))
+	if remainder.Sign() != 0 {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "transfer amount loses precision on target chain", logan.F{
+			"amount":        amount,
+			"from_decimals": fromDecimals,
+			"to_decimals":   toDecimals,
+		})
+	}
+
+	return nil
+}
+
+func pow10(exp int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil)
 }
@@ -0,0 +1,45 @@
+package voter
+
+import (
+	"testing"
+
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func TestCheckDecimals(t *testing.T) {
+	cases := []struct {
+		name         string
+		amount       uint64
+		mintDecimals uint8
+		fromDecimals uint32
+		toDecimals   uint32
+		wantErr      bool
+	}{
+		{name: "same decimals", amount: 123456789, mintDecimals: 9, fromDecimals: 9, toDecimals: 9},
+		{name: "more target decimals", amount: 1, mintDecimals: 6, fromDecimals: 6, toDecimals: 18},
+		{name: "less target decimals divisible", amount: 1000, mintDecimals: 9, fromDecimals: 9, toDecimals: 6},
+		{name: "less target decimals zero amount", amount: 0, mintDecimals: 9, fromDecimals: 9, toDecimals: 6},
+		{name: "less target decimals loses precision", amount: 1001, mintDecimals: 9, fromDecimals: 9, toDecimals: 6, wantErr: true},
+		{name: "target without decimals", amount: 1_000_000_000, mintDecimals: 9, fromDecimals: 9, toDecimals: 0},
+		{name: "target without decimals loses precision", amount: 1_500_000_000, mintDecimals: 9, fromDecimals: 9, toDecimals: 0, wantErr: true},
+		{name: "mint decimals differ from core", amount: 1000, mintDecimals: 6, fromDecimals: 9, toDecimals: 9, wantErr: true},
+		{name: "mint decimals differ from core with same target", amount: 1000, mintDecimals: 9, fromDecimals: 18, toDecimals: 9, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkDecimals(c.amount, c.mintDecimals, c.fromDecimals, c.toDecimals)
+			if !c.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if errors.Cause(err) != verifiers.ErrWrongOperationContent {
+				t.Fatalf("expected ErrWrongOperationContent, got %v", err)
+			}
+		})
+	}
+}
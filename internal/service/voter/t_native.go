@@ -12,6 +12,7 @@ import (
 	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
 	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service"
 	"github.com/rarimo/solana-program-go/contracts/bridge"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
@@ -19,21 +20,25 @@ import (
 )
 
 type nativeOperator struct {
-	chain  string
-	log    *logan.Entry
-	rarimo *grpc.ClientConn
+	chain   string
+	log     *logan.Entry
+	rarimo  *grpc.ClientConn
+	tokens  tokentypes.QueryClient
+	bundles *BundleVerifier
 }
 
-func NewNativeOperator(chain string, log *logan.Entry, rarimo *grpc.ClientConn) *nativeOperator {
+func NewNativeOperator(chain string, log *logan.Entry, rarimo *grpc.ClientConn, tokens tokentypes.QueryClient, bundles *BundleVerifier) *nativeOperator {
 	return &nativeOperator{
-		chain:  chain,
-		log:    log,
-		rarimo: rarimo,
+		chain:   chain,
+		log:     log,
+		rarimo:  rarimo,
+		tokens:  tokens,
+		bundles: bundles,
 	}
 }
 
-func (n *nativeOperator) ParseTransaction(ctx context.Context, accounts []solana.PublicKey, instruction solana.CompiledInstruction, transfer *rarimotypes.Transfer) error {
-	msg, err := n.GetMessage(ctx, accounts, instruction)
+func (n *nativeOperator) ParseTransaction(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction, transfer *rarimotypes.Transfer) error {
+	msg, err := n.GetMessage(ctx, tx, instruction)
 	if err != nil {
 		return errors.Wrap(err, "error getting message")
 	}
@@ -47,16 +52,35 @@ func (n *nativeOperator) ParseTransaction(ctx context.Context, accounts []solana
 	}
 
 	if !proto.Equal(&transferResp.Transfer, transfer) {
-		return verifiers.ErrWrongOperationContent
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "operation content does not match the deposit")
 	}
 
 	return nil
 }
 
-func (n *nativeOperator) GetMessage(ctx context.Context, accounts []solana.PublicKey, instruction solana.CompiledInstruction) (*oracletypes.MsgCreateTransferOp, error) {
+func (n *nativeOperator) GetMessage(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction) (*oracletypes.MsgCreateTransferOp, error) {
+	accounts, err := service.GetInstructionAccounts(tx.Message.AccountKeys, instruction.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
 	var args bridge.DepositNativeArgs
 	if err := borsh.Deserialize(&args, instruction.Data); err != nil {
-		return nil, errors.Wrap(err, "error desser tx args")
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "error desser tx args: "+err.Error())
+	}
+
+	network, err := GetTargetNetwork(ctx, n.tokens, args.NetworkTo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateReceiver(network, args.ReceiverAddress); err != nil {
+		return nil, err
+	}
+
+	hasBundle, err := n.bundles.Verify(network, args.BundleData, args.BundleSeed)
+	if err != nil {
+		return nil, err
 	}
 
 	from := tokentypes.OnChainItemIndex{
@@ -65,7 +89,7 @@ func (n *nativeOperator) GetMessage(ctx context.Context, accounts []solana.Publi
 		TokenID: "",
 	}
 
-	to, err := n.getTo(ctx, args.NetworkTo)
+	to, err := getTargetItem(ctx, n.tokens, &from, args.NetworkTo)
 	if err != nil {
 		return nil, err
 	}
@@ -78,32 +102,10 @@ func (n *nativeOperator) GetMessage(ctx context.Context, accounts []solana.Publi
 		To:       *to,
 	}
 
-	if args.BundleData != nil && len(*args.BundleData) > 0 && args.BundleSeed != nil {
+	if hasBundle {
 		msg.BundleData = hexutil.Encode(*args.BundleData)
 		msg.BundleSalt = hexutil.Encode((*args.BundleSeed)[:])
 	}
 
 	return msg, nil
 }
-
-func (n *nativeOperator) getTo(ctx context.Context, chain string) (*tokentypes.OnChainItemIndex, error) {
-	fromOnChainResp, err := tokentypes.NewQueryClient(n.rarimo).OnChainItem(ctx, &tokentypes.QueryGetOnChainItemRequest{Chain: n.chain})
-	if err != nil {
-		n.log.WithError(err).Error("error fetching on chain item")
-		return nil, verifiers.ErrWrongOperationContent
-	}
-
-	itemResp, err := tokentypes.NewQueryClient(n.rarimo).Item(ctx, &tokentypes.QueryGetItemRequest{Index: fromOnChainResp.Item.Item})
-	if err != nil {
-		n.log.WithError(err).Error("error fetching item")
-		return nil, verifiers.ErrWrongOperationContent
-	}
-
-	for _, index := range itemResp.Item.OnChain {
-		if index.Chain == chain {
-			return index, nil
-		}
-	}
-
-	return nil, verifiers.ErrWrongOperationContent
-}
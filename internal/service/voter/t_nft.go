@@ -12,8 +12,12 @@ import (
 	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	tokentypes "github.com/rarimo/rarimo-core/x/tokenmanager/types"
 	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/config"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"github.com/rarimo/sol-saver-svc/internal/service/metadata"
 	"github.com/rarimo/solana-program-go/contracts/bridge"
 	"github.com/rarimo/solana-program-go/metaplex"
+	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -21,21 +25,31 @@ import (
 )
 
 type nftOperator struct {
-	chain  string
-	solana *rpc.Client
-	rarimo *grpc.ClientConn
+	chain   string
+	log     *logan.Entry
+	solana  *service.Quorum
+	rarimo  *grpc.ClientConn
+	tokens  tokentypes.QueryClient
+	bundles *BundleVerifier
+	conf    config.NFTConf
+	meta    *metadata.Fetcher
 }
 
-func NewNFTOperator(chain string, solana *rpc.Client, rarimo *grpc.ClientConn) *nftOperator {
+func NewNFTOperator(chain string, log *logan.Entry, solana *service.Quorum, rarimo *grpc.ClientConn, tokens tokentypes.QueryClient, conf config.NFTConf, meta *metadata.Fetcher, bundles *BundleVerifier) *nftOperator {
 	return &nftOperator{
-		chain:  chain,
-		solana: solana,
-		rarimo: rarimo,
+		chain:   chain,
+		log:     log,
+		solana:  solana,
+		rarimo:  rarimo,
+		tokens:  tokens,
+		bundles: bundles,
+		conf:    conf,
+		meta:    meta,
 	}
 }
 
-func (f *nftOperator) ParseTransaction(ctx context.Context, accounts []solana.PublicKey, instruction solana.CompiledInstruction, transfer *rarimotypes.Transfer) error {
-	msg, err := f.GetMessage(ctx, accounts, instruction)
+func (f *nftOperator) ParseTransaction(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction, transfer *rarimotypes.Transfer) error {
+	msg, err := f.GetMessage(ctx, tx, instruction)
 	if err != nil {
 		return errors.Wrap(err, "error getting message")
 	}
@@ -49,22 +63,60 @@ func (f *nftOperator) ParseTransaction(ctx context.Context, accounts []solana.Pu
 	}
 
 	if !proto.Equal(&transferResp.Transfer, transfer) {
-		return verifiers.ErrWrongOperationContent
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "operation content does not match the deposit")
 	}
 
 	return nil
 }
 
-func (f *nftOperator) GetMessage(ctx context.Context, accounts []solana.PublicKey, instruction solana.CompiledInstruction) (*oracletypes.MsgCreateTransferOp, error) {
+func (f *nftOperator) GetMessage(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction) (*oracletypes.MsgCreateTransferOp, error) {
+	accounts, err := service.GetInstructionAccounts(tx.Message.AccountKeys, instruction.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
 	var args bridge.DepositNFTArgs
 	if err := borsh.Deserialize(&args, instruction.Data); err != nil {
-		return nil, errors.Wrap(err, "error desser tx args")
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "error desser tx args: "+err.Error())
 	}
 
-	tokenId := hexutil.Encode(accounts[bridge.DepositNFTMintIndex].Bytes())
-	address, err := f.getTokenCollectionAddress(accounts[bridge.DepositNFTMintIndex])
+	network, err := GetTargetNetwork(ctx, f.tokens, args.NetworkTo)
 	if err != nil {
-		return nil, errors.Wrap(err, "error getting collection address")
+		return nil, err
+	}
+
+	if err := ValidateReceiver(network, args.ReceiverAddress); err != nil {
+		return nil, err
+	}
+
+	hasBundle, err := f.bundles.Verify(network, args.BundleData, args.BundleSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	mint := accounts[bridge.DepositNFTMintIndex]
+
+	metadata, err := f.getMetadata(ctx, mint)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting metadata")
+	}
+
+	edition, err := f.verifyToken(ctx, mint, accounts[bridge.DepositNFTBridgeAssocIndex], metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	f.log.WithFields(logan.F{
+		"mint":         mint.String(),
+		"edition":      edition.Kind,
+		"programmable": metadata.IsProgrammable(),
+	}).Debug("NFT deposit token")
+
+	tokenId := hexutil.Encode(mint.Bytes())
+	// Print editions are mapped by their own mint: edition account does not reference the master mint
+	address, err := f.getCollectionAddress(ctx, mint, metadata)
+	if err != nil {
+		return nil, err
 	}
 
 	if address == "" {
@@ -82,7 +134,7 @@ func (f *nftOperator) GetMessage(ctx context.Context, accounts []solana.PublicKe
 		return nil, err
 	}
 
-	meta, err := f.getItemMeta(ctx, &from)
+	meta, err := f.getItemMeta(ctx, &from, metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +148,7 @@ func (f *nftOperator) GetMessage(ctx context.Context, accounts []solana.PublicKe
 		Meta:     meta,
 	}
 
-	if args.BundleData != nil && len(*args.BundleData) > 0 && args.BundleSeed != nil {
+	if hasBundle {
 		msg.BundleData = hexutil.Encode(*args.BundleData)
 		msg.BundleSalt = hexutil.Encode((*args.BundleSeed)[:])
 	}
@@ -130,13 +182,13 @@ func (f *nftOperator) getTargetOnChainItem(ctx context.Context, from *tokentypes
 	// 4. getting target data (should exist)
 	targetDataIndex, err := f.getTargetDataIndex(ctx, from, toChain)
 	if err != nil {
-		return nil, errors.Wrap(err, "unexpected error during fetching target collection data index")
+		return nil, err
 	}
 
 	// 5. getting native collection data (should exist)
 	nativeCollectionData, err := f.getNativeData(ctx, from)
 	if err != nil {
-		return nil, errors.Wrap(err, "unexpected error during fetching native collection data")
+		return nil, err
 	}
 
 	// 6. If its equal to the current chain
@@ -155,7 +207,9 @@ func (f *nftOperator) getTargetOnChainItem(ctx context.Context, from *tokentypes
 	}
 
 	if native == nil {
-		return nil, verifiers.ErrWrongOperationContent
+		return nil, errors.Wrap(ErrTokenNotMapped, "native on chain item is not registered in core", logan.F{
+			"native_chain": nativeCollectionData.Index.Chain,
+		})
 	}
 
 	// TODO manage several solana networks supported
@@ -169,7 +223,7 @@ func (f *nftOperator) getTargetOnChainItem(ctx context.Context, from *tokentypes
 }
 
 func (f *nftOperator) tryGetOnChainItem(ctx context.Context, from *tokentypes.OnChainItemIndex, toChain string) (*tokentypes.OnChainItemIndex, error) {
-	toOnChainItemResp, err := tokentypes.NewQueryClient(f.rarimo).OnChainItemByOther(ctx, &tokentypes.QueryGetOnChainItemByOtherRequest{
+	toOnChainItemResp, err := f.tokens.OnChainItemByOther(ctx, &tokentypes.QueryGetOnChainItemByOtherRequest{
 		Chain:       from.Chain,
 		Address:     from.Address,
 		TokenID:     from.TokenID,
@@ -189,13 +243,19 @@ func (f *nftOperator) tryGetOnChainItem(ctx context.Context, from *tokentypes.On
 }
 
 func (f *nftOperator) getNativeData(ctx context.Context, from *tokentypes.OnChainItemIndex) (*tokentypes.CollectionData, error) {
-	collectionResp, err := tokentypes.NewQueryClient(f.rarimo).CollectionByCollectionData(ctx, &tokentypes.QueryGetCollectionByCollectionDataRequest{Chain: from.Chain, Address: from.Address})
+	collectionResp, err := f.getCollection(ctx, from)
 	if err != nil {
-		return nil, errors.Wrap(err, "error fetching collection")
+		return nil, err
 	}
 
-	nativeCollectionData, err := tokentypes.NewQueryClient(f.rarimo).NativeCollectionData(ctx, &tokentypes.QueryGetNativeCollectionDataRequest{Collection: collectionResp.Collection.Index})
+	nativeCollectionData, err := f.tokens.NativeCollectionData(ctx, &tokentypes.QueryGetNativeCollectionDataRequest{Collection: collectionResp.Collection.Index})
 	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.Wrap(ErrTokenNotMapped, "native collection data is not registered in core", logan.F{
+				"collection": collectionResp.Collection.Index,
+			})
+		}
+
 		return nil, errors.Wrap(err, "error fetching native collection data")
 	}
 
@@ -203,9 +263,9 @@ func (f *nftOperator) getNativeData(ctx context.Context, from *tokentypes.OnChai
 }
 
 func (f *nftOperator) getTargetDataIndex(ctx context.Context, from *tokentypes.OnChainItemIndex, targetChain string) (*tokentypes.CollectionDataIndex, error) {
-	collectionResp, err := tokentypes.NewQueryClient(f.rarimo).CollectionByCollectionData(ctx, &tokentypes.QueryGetCollectionByCollectionDataRequest{Chain: from.Chain, Address: from.Address})
+	collectionResp, err := f.getCollection(ctx, from)
 	if err != nil {
-		return nil, errors.Wrap(err, "error fetching collection")
+		return nil, err
 	}
 
 	for _, index := range collectionResp.Collection.Data {
@@ -214,22 +274,33 @@ func (f *nftOperator) getTargetDataIndex(ctx context.Context, from *tokentypes.O
 		}
 	}
 
-	return nil, verifiers.ErrWrongOperationContent
+	return nil, errors.Wrap(ErrNetworkNotSupported, "collection has no data for target network", logan.F{
+		"collection": collectionResp.Collection.Index,
+		"network":    targetChain,
+	})
 }
 
-func (f *nftOperator) getItemMeta(ctx context.Context, from *tokentypes.OnChainItemIndex) (*tokentypes.ItemMetadata, error) {
+func (f *nftOperator) getCollection(ctx context.Context, from *tokentypes.OnChainItemIndex) (*tokentypes.QueryGetCollectionByCollectionDataResponse, error) {
+	collectionResp, err := f.tokens.CollectionByCollectionData(ctx, &tokentypes.QueryGetCollectionByCollectionDataRequest{Chain: from.Chain, Address: from.Address})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.Wrap(ErrTokenNotMapped, "collection is not registered in core", logan.F{"address": from.Address})
+		}
+
+		return nil, errors.Wrap(err, "error fetching collection")
+	}
+
+	return collectionResp, nil
+}
+
+func (f *nftOperator) getItemMeta(ctx context.Context, from *tokentypes.OnChainItemIndex, metadata *Metadata) (*tokentypes.ItemMetadata, error) {
 	// return empty meta if should not been provided
-	_, err := tokentypes.NewQueryClient(f.rarimo).OnChainItem(ctx, &tokentypes.QueryGetOnChainItemRequest{Chain: from.Chain, Address: from.Address, TokenID: from.TokenID})
+	_, err := f.tokens.OnChainItem(ctx, &tokentypes.QueryGetOnChainItemRequest{Chain: from.Chain, Address: from.Address, TokenID: from.TokenID})
 	if err == nil {
 		return nil, nil
 	}
 
-	metadata, err := f.getMetadata(solana.MustPublicKeyFromBase58(from.TokenID))
-	if err != nil {
-		return nil, errors.Wrap(err, "error fetching metadata from chain")
-	}
-
-	imageUrl, imageHash, err := verifiers.GetImage(metadata.Data.URI)
+	imageUrl, imageHash, err := f.meta.GetImage(ctx, metadata.Data.URI)
 	if err != nil {
 		return nil, errors.Wrap(err, "error fetching image")
 	}
@@ -242,31 +313,188 @@ func (f *nftOperator) getItemMeta(ctx context.Context, from *tokentypes.OnChainI
 	}, nil
 }
 
-func (f *nftOperator) getMetadata(mint solana.PublicKey) (*metaplex.Metadata, error) {
+func (f *nftOperator) getMetadata(ctx context.Context, mint solana.PublicKey) (*Metadata, error) {
 	metadataAddress, _, err := solana.FindTokenMetadataAddress(mint)
 	if err != nil {
 		return nil, errors.Wrap(err, "error generating metadata key")
 	}
 
-	metadataInfo, err := f.solana.GetAccountInfo(context.TODO(), metadataAddress)
+	metadataInfo, err := f.solana.GetAccountData(ctx, metadataAddress)
 	if err != nil {
 		return nil, errors.Wrap(err, "error fetching metadata account info")
 	}
 
-	metadata := new(metaplex.Metadata)
-	return metadata, borsh.Deserialize(metadata, metadataInfo.Value.Data.GetBinary())
+	metadata := new(Metadata)
+	return metadata, borsh.Deserialize(metadata, metadataInfo)
 }
 
-func (f *nftOperator) getTokenCollectionAddress(mint solana.PublicKey) (string, error) {
-	metadata, err := f.getMetadata(mint)
+// getEdition returns the master or print edition of the mint or <nil> if mint has no edition account.
+func (f *nftOperator) getEdition(ctx context.Context, mint solana.PublicKey) (*Edition, error) {
+	editionAddress, _, err := metaplex.FindTokenMasterEditionAddress(mint)
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating edition key")
+	}
+
+	editionInfo, err := f.solana.GetAccountData(ctx, editionAddress)
+	if err != nil {
+		if errors.Cause(err) == rpc.ErrNotFound {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "error fetching edition account info")
+	}
+
+	edition, err := ParseEdition(editionInfo)
 	if err != nil {
-		return "", errors.Wrap(err, "error getting metadata")
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, err.Error())
+	}
+
+	return edition, nil
+}
+
+// verifyToken checks that the deposited token is a non-fungible one and its edition matches the token standard.
+// Programmable NFTs should be governed by one of allowed rule sets and should be unlocked and not delegated in the bridge vault.
+func (f *nftOperator) verifyToken(ctx context.Context, mint, vault solana.PublicKey, metadata *Metadata) (*Edition, error) {
+	if metadata.IsFungible() {
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "fungible token standard is not supported for NFT deposits")
 	}
 
-	// TODO maybe check for zero collection address instead of verified value
-	if metadata.Collection == nil || !metadata.Collection.Verified {
-		return "", nil
+	edition, err := f.getEdition(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+
+	if edition == nil {
+		// Legacy tokens could be created without editions. Token standard None is decoded as NonFungible.
+		if metadata.TokenStandard != nil && *metadata.TokenStandard != metaplex.NonFungible {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "edition account is missing")
+		}
+
+		edition = &Edition{Kind: EditionNone}
+	}
+
+	if metadata.TokenStandard != nil {
+		switch *metadata.TokenStandard {
+		case metaplex.NonFungibleEdition, ProgrammableNonFungibleEdition:
+			if edition.Kind != EditionPrint {
+				return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "print edition token standard does not match edition account", logan.F{"edition": edition.Kind})
+			}
+		case ProgrammableNonFungible:
+			if edition.Kind != EditionMaster && edition.Kind != EditionUnique {
+				return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "master edition token standard does not match edition account", logan.F{"edition": edition.Kind})
+			}
+		}
+	}
+
+	if metadata.IsProgrammable() {
+		if err := f.verifyProgrammable(ctx, mint, vault, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return edition, nil
+}
+
+func (f *nftOperator) verifyProgrammable(ctx context.Context, mint, vault solana.PublicKey, metadata *Metadata) error {
+	if ruleSet := metadata.RuleSet(); !ruleSet.IsZero() {
+		if _, ok := f.conf.RuleSets[ruleSet]; !ok {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "programmable NFT rule set is not allowed", logan.F{"rule_set": ruleSet.String()})
+		}
+	}
+
+	recordAddress, _, err := FindTokenRecordAddress(mint, vault)
+	if err != nil {
+		return errors.Wrap(err, "error generating token record key")
+	}
+
+	recordInfo, err := f.solana.GetAccountData(ctx, recordAddress)
+	if err != nil {
+		if errors.Cause(err) == rpc.ErrNotFound {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "vault token record is missing")
+		}
+
+		return errors.Wrap(err, "error fetching token record account info")
+	}
+
+	record := new(TokenRecord)
+	if err := borsh.Deserialize(record, recordInfo); err != nil {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "invalid token record")
+	}
+
+	if record.Key != KeyTokenRecord {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "account is not a token record")
+	}
+
+	if record.State != TokenStateUnlocked {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "vault token is locked or listed", logan.F{"state": record.State})
+	}
+
+	if record.Delegate != nil && !record.Delegate.IsZero() {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "vault token is delegated", logan.F{"delegate": record.Delegate.String()})
+	}
+
+	return nil
+}
+
+// getCollectionAddress returns the hex encoded collection address of the token according to the collection policy.
+// Empty address means that the token is a standalone item.
+func (f *nftOperator) getCollectionAddress(ctx context.Context, mint solana.PublicKey, metadata *Metadata) (string, error) {
+	decision, err := f.decideCollection(ctx, metadata)
+
+	CollectionDecisionsMetric.WithLabelValues(string(f.conf.CollectionPolicy), decision).Inc()
+	f.log.WithFields(logan.F{
+		"mint":                mint.String(),
+		"collection":          metadata.Collection.Address.String(),
+		"collection_verified": metadata.Collection.Verified,
+		"policy":              f.conf.CollectionPolicy,
+		"decision":            decision,
+	}).Info("NFT collection policy applied")
+
+	if err != nil {
+		return "", err
+	}
+
+	switch decision {
+	case CollectionDecisionVerified, CollectionDecisionAllowlisted:
+		return hexutil.Encode(metadata.Collection.Address.Bytes()), nil
+	}
+
+	return "", nil
+}
+
+func (f *nftOperator) decideCollection(ctx context.Context, metadata *Metadata) (string, error) {
+	// Missing collection is decoded as zero unverified collection
+	if metadata.Collection == nil {
+		metadata.Collection = &metaplex.Collection{}
+	}
+
+	if metadata.Collection.Verified {
+		return CollectionDecisionVerified, nil
+	}
+
+	switch f.conf.CollectionPolicy {
+	case config.CollectionPolicyStandalone:
+		return CollectionDecisionStandalone, nil
+	case config.CollectionPolicyAllowlist:
+		if _, ok := f.conf.Collections[metadata.Collection.Address]; !ok {
+			return CollectionDecisionRejected, errors.Wrap(verifiers.ErrWrongOperationContent, "NFT collection is not verified and not allowed")
+		}
+
+		// Anyone can set the unverified collection, so the token should be issued by the collection authority
+		collection, err := f.getMetadata(ctx, metadata.Collection.Address)
+		if err != nil {
+			return CollectionDecisionRejected, errors.Wrap(err, "error getting collection metadata")
+		}
+
+		if collection.UpdateAuthority != metadata.UpdateAuthority {
+			return CollectionDecisionRejected, errors.Wrap(verifiers.ErrWrongOperationContent, "NFT update authority does not match allowed collection", logan.F{
+				"update_authority":            metadata.UpdateAuthority.String(),
+				"collection_update_authority": collection.UpdateAuthority.String(),
+			})
+		}
+
+		return CollectionDecisionAllowlisted, nil
 	}
 
-	return hexutil.Encode(metadata.Collection.Address.Bytes()), nil
+	return CollectionDecisionRejected, errors.Wrap(verifiers.ErrWrongOperationContent, "NFT collection is not verified")
 }
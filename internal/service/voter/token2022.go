@@ -0,0 +1,112 @@
+package voter
+
+import (
+	"context"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
+	"github.com/rarimo/sol-saver-svc/internal/service"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// DepositFTTokenProgramIndex is the index of the token program (SPL Token or Token-2022) in the deposit FT instruction accounts
+const DepositFTTokenProgramIndex = 5
+
+// VerifyToken2022Mint checks that the Token-2022 mint extensions do not prevent the bridging of its tokens
+// and returns the mint transfer fee configuration (<nil> if mint has no transfer fees).
+//
+// Deposits are refused for:
+//   - non-transferable and interest-bearing mints (bridged amount does not represent the same value on other chains);
+//   - mints with the permanent delegate or transfer hook (vault tokens can be moved or their withdrawal blocked by third party).
+//
+// Confidential transfers are allowed: deposits are verified by the public vault balance changes only.
+func VerifyToken2022Mint(ctx context.Context, quorum *service.Quorum, mint solana.PublicKey) (*service.TransferFeeConfig, error) {
+	account, err := quorum.GetAccount(ctx, mint)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting mint account")
+	}
+
+	if account.Owner != service.Token2022ProgramID {
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "mint is not owned by Token-2022 program", logan.F{"owner": account.Owner.String()})
+	}
+
+	extensions, err := service.ParseMintExtensions(account.Data)
+	if err != nil {
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, err.Error())
+	}
+
+	if _, ok := extensions[service.ExtensionNonTransferable]; ok {
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "Token-2022 non-transferable mints are not supported")
+	}
+
+	if _, ok := extensions[service.ExtensionInterestBearingConfig]; ok {
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "Token-2022 interest-bearing mints are not supported")
+	}
+
+	if data, ok := extensions[service.ExtensionPermanentDelegate]; ok {
+		delegate, err := service.ParsePermanentDelegate(data)
+		if err != nil {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, err.Error())
+		}
+
+		if !delegate.IsZero() {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "Token-2022 mints with permanent delegate are not supported", logan.F{
+				"delegate": delegate.String(),
+			})
+		}
+	}
+
+	if data, ok := extensions[service.ExtensionTransferHook]; ok {
+		program, err := service.ParseTransferHookProgram(data)
+		if err != nil {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, err.Error())
+		}
+
+		if !program.IsZero() {
+			return nil, errors.Wrap(verifiers.ErrWrongOperationContent, "Token-2022 mints with transfer hook are not supported", logan.F{
+				"hook_program": program.String(),
+			})
+		}
+	}
+
+	data, ok := extensions[service.ExtensionTransferFeeConfig]
+	if !ok {
+		return nil, nil
+	}
+
+	fees, err := service.ParseTransferFeeConfig(data)
+	if err != nil {
+		return nil, errors.Wrap(verifiers.ErrWrongOperationContent, err.Error())
+	}
+
+	return fees, nil
+}
+
+// verifyTransferFee checks that the vault has received the deposited amount minus the transfer fee.
+// Fee configuration could be changed since the deposit, so both older and newer fees are accepted.
+func verifyTransferFee(fees *service.TransferFeeConfig, amount, received uint64) error {
+	if fees == nil {
+		if received != amount {
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "vault received amount mismatch", logan.F{
+				"expected": amount,
+				"received": received,
+			})
+		}
+
+		return nil
+	}
+
+	for _, fee := range []service.TransferFee{fees.Older, fees.Newer} {
+		if amount-fee.Calculate(amount) == received {
+			return nil
+		}
+	}
+
+	return errors.Wrap(verifiers.ErrWrongOperationContent, "vault received amount does not match transfer fee", logan.F{
+		"amount":    amount,
+		"received":  received,
+		"older_fee": fees.Older.Calculate(amount),
+		"newer_fee": fees.Newer.Calculate(amount),
+	})
+}
@@ -3,78 +3,152 @@ package voter
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/olegfomenko/solana-go"
-	"github.com/olegfomenko/solana-go/rpc"
 	rarimotypes "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	"github.com/rarimo/saver-grpc-lib/voter/verifiers"
 	"github.com/rarimo/sol-saver-svc/internal/config"
 	"github.com/rarimo/sol-saver-svc/internal/service"
 	"github.com/rarimo/solana-program-go/contracts/bridge"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
 const DataInstructionCodeIndex = 0
 
 type IOperator interface {
-	ParseTransaction(ctx context.Context, accounts []solana.PublicKey, instruction solana.CompiledInstruction, transfer *rarimotypes.Transfer) error
+	ParseTransaction(ctx context.Context, tx *service.Transaction, instruction solana.CompiledInstruction, transfer *rarimotypes.Transfer) error
 }
 
 type TransferOperator struct {
-	solana    *rpc.Client
+	log       *logan.Entry
+	solana    *service.Quorum
 	program   solana.PublicKey
 	chain     string
+	logs      *LogsVerifier
 	operators map[bridge.Instruction]IOperator
+
+	// pool limits the amount of concurrently verified operations
+	pool    chan struct{}
+	timeout time.Duration
 }
 
 func NewTransferOperator(cfg config.Config) *TransferOperator {
+	quorum := newQuorum(cfg)
+	bundles := NewBundleVerifier(cfg)
+
 	return &TransferOperator{
-		solana:  cfg.SolanaRPC(),
+		log:     cfg.Log(),
+		solana:  quorum,
 		program: cfg.ListenConf().ProgramId,
 		chain:   cfg.ListenConf().Chain,
+		logs:    NewLogsVerifier(cfg),
+		pool:    make(chan struct{}, cfg.VoterConf().Workers),
+		timeout: cfg.VoterConf().Timeout,
 		operators: map[bridge.Instruction]IOperator{
-			bridge.InstructionDepositNative: NewNativeOperator(cfg.ListenConf().Chain, cfg.Log(), cfg.Cosmos()),
-			bridge.InstructionDepositFT:     NewFTOperator(cfg.ListenConf().Chain, cfg.Log(), cfg.Cosmos()),
-			bridge.InstructionDepositNFT:    NewNFTOperator(cfg.ListenConf().Chain, cfg.SolanaRPC(), cfg.Cosmos()),
+			bridge.InstructionDepositNative: NewNativeOperator(cfg.ListenConf().Chain, cfg.Log(), cfg.Cosmos(), cfg.TokenManager(), bundles),
+			bridge.InstructionDepositFT:     NewFTOperator(cfg.ListenConf().Chain, cfg.Log(), quorum, cfg.Cosmos(), cfg.TokenManager(), bundles),
+			bridge.InstructionDepositNFT:    NewNFTOperator(cfg.ListenConf().Chain, cfg.Log(), quorum, cfg.Cosmos(), cfg.TokenManager(), cfg.NFTConf(), cfg.MetadataFetcher(), bundles),
 		},
 	}
 }
 
+// newQuorum returns the quorum of configured Solana providers
+// or the single default provider if quorum verification is disabled.
+func newQuorum(cfg config.Config) *service.Quorum {
+	conf := cfg.SolanaQuorum()
+	if len(conf.Providers) == 0 {
+		return service.NewQuorum(cfg.Log(), 1, cfg.SolanaRPC())
+	}
+
+	return service.NewQuorum(cfg.Log(), conf.Threshold, conf.Providers...)
+}
+
 // Implements verifiers.ITransferOperator
 var _ verifiers.TransferOperator = &TransferOperator{}
 
+// VerifyTransfer waits for the free worker in the pool and verifies the transfer within the configured timeout.
 func (t *TransferOperator) VerifyTransfer(ctx context.Context, tx, eventId string, transfer *rarimotypes.Transfer) error {
+	select {
+	case t.pool <- struct{}{}:
+		defer func() { <-t.pool }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.verifyTransfer(ctx, tx, eventId, transfer)
+}
+
+func (t *TransferOperator) verifyTransfer(ctx context.Context, tx, eventId string, transfer *rarimotypes.Transfer) error {
 	if transfer.From.Chain != t.chain {
 		return verifiers.ErrUnsupportedNetwork
 	}
 
 	sig, err := solana.SignatureFromBase58(tx)
 	if err != nil {
-		return err
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "invalid transaction signature")
 	}
 
 	msgId, err := strconv.Atoi(eventId)
 	if err != nil {
-		return err
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "invalid event id")
+	}
+
+	if msgId < 0 {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "negative event id")
 	}
 
-	transaction, err := service.GetTransaction(ctx, t.solana, sig)
+	transaction, err := t.solana.GetTransaction(ctx, sig, msgId)
 	if err != nil {
 		return err
 	}
 
+	if transaction == nil || msgId >= len(transaction.Message.Instructions) {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "deposit instruction not found")
+	}
+
+	if err := VerifyBalances(t.program, transaction); err != nil {
+		t.log.WithError(err).WithFields(logan.F{"tx": tx, "event_id": eventId}).Error("deposit rejected: balance mismatch")
+		return err
+	}
+
 	instruction := transaction.Message.Instructions[msgId]
 
+	if int(instruction.ProgramIDIndex) >= len(transaction.Message.AccountKeys) || len(instruction.Data) == 0 {
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "malformed deposit instruction")
+	}
+
 	if transaction.Message.AccountKeys[instruction.ProgramIDIndex] != t.program {
-		return verifiers.ErrWrongOperationContent
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "instruction of another program")
 	}
 
 	operator, ok := t.operators[bridge.Instruction(instruction.Data[DataInstructionCodeIndex])]
 	if !ok {
-		return verifiers.ErrWrongOperationContent
+		return errors.Wrap(verifiers.ErrWrongOperationContent, "unknown bridge instruction")
+	}
+
+	if err := VerifyAccounts(transaction, instruction); err != nil {
+		t.log.WithError(err).WithFields(logan.F{"tx": tx, "event_id": eventId}).Error("deposit rejected: invalid accounts")
+		return err
+	}
+
+	if err := t.logs.Verify(transaction, msgId); err != nil {
+		t.log.WithError(err).WithFields(logan.F{"tx": tx, "event_id": eventId}).Error("deposit rejected: logs mismatch")
+		return err
+	}
+
+	if err := operator.ParseTransaction(ctx, transaction, instruction, transfer); err != nil {
+		if reason, ok := RefundReason(err); ok {
+			t.log.WithError(err).WithFields(logan.F{"tx": tx, "event_id": eventId, "reason": reason}).Error("deposit rejected: should be refunded")
+			return errors.Wrap(verifiers.ErrWrongOperationContent, "deposit should be refunded: "+reason)
+		}
+
+		return err
 	}
 
-	return operator.ParseTransaction(ctx,
-		service.GetInstructionAccounts(transaction.Message.AccountKeys, instruction.Accounts),
-		instruction,
-		transfer)
+	return nil
 }
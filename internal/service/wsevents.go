@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const wsDialTimeout = 10 * time.Second
+
+// WSEvents is the tendermint events client dialing the websocket with the custom TLS config.
+// Tendermint http client dials wss:// subscriptions with the default TLS config only,
+// so the private CA and client certificates can not be used there.
+type WSEvents struct {
+	log *logan.Entry
+	ws  *jsonrpcclient.WSClient
+
+	mu            sync.RWMutex
+	subscriptions map[string]chan ctypes.ResultEvent
+}
+
+var _ rpcclient.EventsClient = &WSEvents{}
+
+// NewWSEvents creates the events client for the https:// remote, websocket connection is established over TLS
+// using the provided config. Call Start before subscribing.
+func NewWSEvents(log *logan.Entry, remote, endpoint string, tlsConfig *tls.Config) (*WSEvents, error) {
+	u, err := url.Parse(remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing remote address")
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	config := tlsConfig.Clone()
+	if config.ServerName == "" {
+		config.ServerName = u.Hostname()
+	}
+
+	w := &WSEvents{
+		log:           log,
+		subscriptions: make(map[string]chan ctypes.ResultEvent),
+	}
+
+	// Plain ws:// client with the TLS dialer, otherwise the websocket dialer would wrap the connection
+	// into TLS with the default config.
+	w.ws, err = jsonrpcclient.NewWS("tcp://"+host, endpoint, jsonrpcclient.OnReconnect(func() {
+		w.resubscribe()
+	}))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating websocket client")
+	}
+
+	w.ws.Dialer = func(string, string) (net.Conn, error) {
+		return tls.DialWithDialer(&net.Dialer{Timeout: wsDialTimeout}, "tcp", host, config)
+	}
+
+	return w, nil
+}
+
+// Start connects to the websocket and starts dispatching events to the subscriptions.
+func (w *WSEvents) Start() error {
+	if err := w.ws.Start(); err != nil {
+		return errors.Wrap(err, "error starting websocket client")
+	}
+
+	go w.listen()
+	return nil
+}
+
+// Subscribe subscribes to the query, subscriber is ignored as tendermint overrides it with the remote address.
+func (w *WSEvents) Subscribe(ctx context.Context, _, query string, outCapacity ...int) (<-chan ctypes.ResultEvent, error) {
+	if err := w.ws.Subscribe(ctx, query); err != nil {
+		return nil, err
+	}
+
+	capacity := 1
+	if len(outCapacity) > 0 {
+		capacity = outCapacity[0]
+	}
+
+	out := make(chan ctypes.ResultEvent, capacity)
+
+	w.mu.Lock()
+	w.subscriptions[query] = out
+	w.mu.Unlock()
+
+	return out, nil
+}
+
+func (w *WSEvents) Unsubscribe(ctx context.Context, _, query string) error {
+	if err := w.ws.Unsubscribe(ctx, query); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	delete(w.subscriptions, query)
+	w.mu.Unlock()
+
+	return nil
+}
+
+func (w *WSEvents) UnsubscribeAll(ctx context.Context, _ string) error {
+	if err := w.ws.UnsubscribeAll(ctx); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.subscriptions = make(map[string]chan ctypes.ResultEvent)
+	w.mu.Unlock()
+
+	return nil
+}
+
+// resubscribe restores the subscriptions after the reconnect.
+func (w *WSEvents) resubscribe() {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for query := range w.subscriptions {
+		if err := w.ws.Subscribe(context.Background(), query); err != nil {
+			w.log.WithError(err).WithField("query", query).Error("failed to resubscribe")
+		}
+	}
+}
+
+func (w *WSEvents) listen() {
+	for {
+		select {
+		case <-w.ws.Quit():
+			return
+		case resp, ok := <-w.ws.ResponsesCh:
+			if !ok {
+				return
+			}
+
+			if resp.Error != nil {
+				w.log.WithError(resp.Error).Error("websocket error")
+				// Subscriptions are lost if tendermint has restarted, giving it time to start
+				if !strings.Contains(resp.Error.Error(), tmpubsub.ErrAlreadySubscribed.Error()) {
+					time.Sleep(time.Second)
+					w.resubscribe()
+				}
+				continue
+			}
+
+			var event ctypes.ResultEvent
+			if err := tmjson.Unmarshal(resp.Result, &event); err != nil {
+				w.log.WithError(err).Error("failed to unmarshal event")
+				continue
+			}
+
+			w.mu.RLock()
+			if out, ok := w.subscriptions[event.Query]; ok {
+				select {
+				case out <- event:
+				default:
+					w.log.WithField("query", event.Query).Error("subscription channel is full, event dropped")
+				}
+			}
+			w.mu.RUnlock()
+		}
+	}
+}